@@ -6,21 +6,33 @@ import (
 	"fmt"
 	"log"
 	"os"
+	"time"
 
 	"github.com/dukerupert/faa-aircraft-search/internal/database"
+	"github.com/dukerupert/faa-aircraft-search/internal/diff"
 	"github.com/dukerupert/faa-aircraft-search/internal/migration"
 )
 
 func main() {
 	var (
-		action   = flag.String("action", "", "Action to perform: import, clear, count")
-		filePath = flag.String("file", "aircraft_data.xlsx", "Path to Excel file for import")
+		action     = flag.String("action", "", "Action to perform: import, diff, rollback, benchmark, clear, count")
+		filePath   = flag.String("file", "aircraft_data.xlsx", "Path to the xlsx, csv, json or ndjson file for import or diff; - reads standard input")
+		format     = flag.String("format", "", "Format of the file: xlsx, csv, json or ndjson (default from the file extension or content)")
+		output     = flag.String("output", "text", "Output format of diff: text, json or markdown")
+		columns    = flag.String("columns", "", "Path to a JSON column mapping applied over the default headers")
+		dryRun     = flag.Bool("dry-run", false, "Validate the file for import without writing to the database")
+		reportPath = flag.String("report", "", "Path to write the JSON validation report of an import to")
+		maxErrors  = flag.Int("max-errors", 0, "Refuse the import when the file has more problems; -1 allows any number")
+		bulk       = flag.Bool("bulk", false, "Import with COPY into a temporary table and a single merge")
 	)
 	flag.Parse()
 
 	if *action == "" {
 		fmt.Println("Usage:")
-		fmt.Println("  go run cmd/migrate/main.go -action=import [-file=path/to/file.xlsx]")
+		fmt.Println("  go run cmd/migrate/main.go -action=import [-file=path/to/file.xlsx|-] [-format=xlsx|csv|json|ndjson] [-columns=mapping.json] [-dry-run] [-report=report.json] [-max-errors=N] [-bulk]")
+		fmt.Println("  go run cmd/migrate/main.go -action=diff [-file=path/to/file.xlsx|-] [-format=xlsx|csv|json|ndjson] [-output=text|json|markdown] [-columns=mapping.json]")
+		fmt.Println("  go run cmd/migrate/main.go -action=rollback")
+		fmt.Println("  go run cmd/migrate/main.go -action=benchmark [-file=path/to/file.xlsx|-] [-format=xlsx|csv|json|ndjson]")
 		fmt.Println("  go run cmd/migrate/main.go -action=clear")
 		fmt.Println("  go run cmd/migrate/main.go -action=count")
 		os.Exit(1)
@@ -28,6 +40,26 @@ func main() {
 
 	ctx := context.Background()
 
+	opts := migration.Options{MaxErrors: *maxErrors, Bulk: *bulk}
+	if *columns != "" {
+		mapping, err := migration.LoadColumns(*columns)
+		if err != nil {
+			log.Fatal(err)
+		}
+		opts.Columns = mapping
+	}
+
+	// A dry run only reads the file, so it needs no database
+	if *action == "import" && *dryRun {
+		report, err := migration.Validate(openSource(*filePath, *format), opts)
+		writeReport(report, *reportPath)
+		if err != nil {
+			log.Fatal("Validation failed: ", err)
+		}
+		fmt.Println("Validation completed successfully!")
+		return
+	}
+
 	// Initialize database connection with new structure
 	db, err := database.InitDatabase(ctx)
 	if err != nil {
@@ -37,12 +69,54 @@ func main() {
 
 	switch *action {
 	case "import":
-		err = migration.MigrateFromExcel(ctx, db, *filePath)
+		report, err := migration.MigrateFromSource(ctx, db, openSource(*filePath, *format), opts)
+		writeReport(report, *reportPath)
 		if err != nil {
 			log.Fatal("Migration failed:", err)
 		}
 		fmt.Println("Migration completed successfully!")
 
+	case "diff":
+		outputFormat, err := diff.ParseFormat(*output)
+		if err != nil {
+			log.Fatal(err)
+		}
+
+		incoming, err := migration.ReadAll(openSource(*filePath, *format), opts)
+		if err != nil {
+			log.Fatal("Failed to read file:", err)
+		}
+
+		report, err := diff.CompareCurrent(ctx, db.Pool, incoming)
+		if err != nil {
+			log.Fatal("Failed to compare data:", err)
+		}
+		if err := diff.Write(os.Stdout, report, outputFormat); err != nil {
+			log.Fatal("Failed to write diff:", err)
+		}
+
+	case "rollback":
+		version, err := migration.Rollback(ctx, db)
+		if err != nil {
+			log.Fatal("Rollback failed: ", err)
+		}
+		fmt.Printf("Restored dataset version %d (%s, imported %s)\n",
+			version.ID, version.SourceFile, version.ImportedAt.Time.Format(time.RFC3339))
+
+	case "benchmark":
+		timings, err := migration.Benchmark(ctx, db, openSource(*filePath, *format), opts)
+		if err != nil {
+			log.Fatal("Benchmark failed: ", err)
+		}
+		fmt.Println("Import paths, fastest of several runs; nothing was written:")
+		for _, t := range timings {
+			fmt.Printf("  %-10s %6d rows in %-12v %8.0f rows/s\n",
+				t.Path, t.Rows, t.Duration.Round(time.Millisecond), float64(t.Rows)/t.Duration.Seconds())
+		}
+		if len(timings) == 2 && timings[1].Duration > 0 {
+			fmt.Printf("  bulk is %.1fx faster\n", float64(timings[0].Duration)/float64(timings[1].Duration))
+		}
+
 	case "clear":
 		err = migration.ClearData(ctx, db)
 		if err != nil {
@@ -60,4 +134,57 @@ func main() {
 	default:
 		log.Fatal("Unknown action:", *action)
 	}
-}
\ No newline at end of file
+}
+
+// openSource reads the file to import, or standard input for -file=-, in
+// the given format or the one its extension implies
+func openSource(path, format string) *migration.Source {
+	var inputFormat migration.Format
+	if format != "" {
+		f, err := migration.ParseFormat(format)
+		if err != nil {
+			log.Fatal(err)
+		}
+		inputFormat = f
+	}
+
+	src, err := migration.OpenSource(path, inputFormat)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return src
+}
+
+// maxListedProblems is how many problems are printed before deferring to
+// the report file
+const maxListedProblems = 20
+
+// writeReport prints a summary of a validation report and writes it to
+// path when one is given
+func writeReport(report *migration.Report, path string) {
+	if report == nil {
+		return
+	}
+
+	fmt.Printf("Rows: %d, imported: %d, errors: %d\n", report.Rows, report.Imported, report.Errors)
+	for i, p := range report.Problems {
+		if i == maxListedProblems {
+			fmt.Printf("  ... and %d more\n", len(report.Problems)-i)
+			break
+		}
+		if p.Column != "" {
+			fmt.Printf("  row %d, %s %q: %s\n", p.Row, p.Column, p.Value, p.Message)
+		} else {
+			fmt.Printf("  row %d: %s\n", p.Row, p.Message)
+		}
+	}
+
+	if path == "" {
+		return
+	}
+	if err := report.WriteFile(path); err != nil {
+		log.Printf("Failed to write report: %v", err)
+		return
+	}
+	fmt.Printf("Report written to %s\n", path)
+}
@@ -12,9 +12,7 @@ import (
 	"github.com/dukerupert/faa-aircraft-search/internal/database"
 	"github.com/dukerupert/faa-aircraft-search/internal/handler"
 	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
-	"github.com/labstack/echo/v4"
-	echomiddleware "github.com/labstack/echo/v4/middleware"
-	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/dukerupert/faa-aircraft-search/internal/server"
 )
 
 func main() {
@@ -32,52 +30,11 @@ func main() {
 		middleware.UpdateTotalAircraftCount(float64(count))
 	}
 
-	// Create Echo instance
-	e := echo.New()
+	// Initialize handler with database and environment configuration
+	h := handler.NewWithConfig(db, handler.ConfigFromEnv())
 
-	// Middleware
-	e.Use(echomiddleware.Logger())
-	e.Use(echomiddleware.Recover())
-	e.Use(echomiddleware.CORS())
-
-	// Prometheus metrics middleware
-	e.Use(middleware.PrometheusMiddleware())
-
-	// Request timeout middleware
-	e.Use(echomiddleware.TimeoutWithConfig(echomiddleware.TimeoutConfig{
-		Timeout: 30 * time.Second,
-	}))
-
-	// Static file serving (for any additional static assets)
-	e.Static("/static", "web/static")
-
-	// Initialize handler with database
-	h := handler.New(db)
-
-	// Metrics endpoint (exclude from metrics middleware to avoid recursion)
-	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
-
-	// Web routes (HTML pages)
-	e.GET("/", h.Home)
-	e.GET("/search", h.Search)
-	e.GET("/aircraft-list", h.AircraftList)
-	e.GET("/aircraft-details/:id", h.AircraftDetails)
-
-	// Base health check route
-	e.GET("/health", h.HealthCheck)
-
-	// API v1 routes
-	v1 := e.Group("/api/v1")
-	{
-		aircraft := v1.Group("/aircraft")
-		{
-			aircraft.GET("/search", h.SearchAircraft)
-			aircraft.GET("/:id", h.GetAircraft)
-		}
-	}
-
-	// Static file serving (for any additional static assets)
-	e.Static("/static", "web/static")
+	// Create Echo instance with middleware and routes
+	e := server.New(h)
 
 	// Start server in a goroutine
 	go func() {
@@ -0,0 +1,330 @@
+// Package classify derives the FAA Aircraft Approach Category (AAC),
+// Airplane Design Group (ADG) and Taxiway Design Group (TDG) of an aircraft
+// from its dimensions, following the tables of FAA AC 150/5300-13.
+package classify
+
+import (
+	"fmt"
+	"math"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Input fields, also reported as the driver of a result
+const (
+	InputApproachSpeed     = "approach_speed_knot"
+	InputWingspan          = "wingspan_ft"
+	InputTailHeight        = "tail_height_at_oew_ft"
+	InputMainGearWidth     = "main_gear_width_ft"
+	InputCockpitToMainGear = "cockpit_to_main_gear_ft"
+)
+
+// Input holds the dimensions used for classification. Any field may be
+// omitted, in which case the groups depending on it are not derived.
+type Input struct {
+	ApproachSpeedKnot   *float64 `json:"approach_speed_knot"`
+	WingspanFt          *float64 `json:"wingspan_ft"`
+	TailHeightAtOewFt   *float64 `json:"tail_height_at_oew_ft"`
+	MainGearWidthFt     *float64 `json:"main_gear_width_ft"`
+	CockpitToMainGearFt *float64 `json:"cockpit_to_main_gear_ft"`
+}
+
+// IsEmpty reports whether no dimension was given
+func (in Input) IsEmpty() bool {
+	return in.ApproachSpeedKnot == nil && in.WingspanFt == nil && in.TailHeightAtOewFt == nil &&
+		in.MainGearWidthFt == nil && in.CockpitToMainGearFt == nil
+}
+
+// ToImperial converts an input given in the unit system to the feet and
+// knots the classification tables use
+func (in Input) ToImperial(s units.System) Input {
+	convert := func(v *float64, q units.Quantity) *float64 {
+		if v == nil {
+			return nil
+		}
+		f := q.ToImperial(*v, s)
+		return &f
+	}
+	return Input{
+		ApproachSpeedKnot:   convert(in.ApproachSpeedKnot, units.Speed),
+		WingspanFt:          convert(in.WingspanFt, units.Length),
+		TailHeightAtOewFt:   convert(in.TailHeightAtOewFt, units.Length),
+		MainGearWidthFt:     convert(in.MainGearWidthFt, units.Length),
+		CockpitToMainGearFt: convert(in.CockpitToMainGearFt, units.Length),
+	}
+}
+
+// Result is a derived group and the input that determined it
+type Result struct {
+	Group    string `json:"group"`
+	DrivenBy string `json:"driven_by"`
+}
+
+// Classification holds the groups that could be derived from an Input
+type Classification struct {
+	AAC *Result `json:"aac,omitempty"`
+	ADG *Result `json:"adg,omitempty"`
+	TDG *Result `json:"tdg,omitempty"`
+}
+
+// Classify derives every group its inputs allow
+func Classify(in Input) (Classification, error) {
+	var c Classification
+
+	for _, input := range []struct {
+		name  string
+		value *float64
+	}{
+		{InputApproachSpeed, in.ApproachSpeedKnot},
+		{InputWingspan, in.WingspanFt},
+		{InputTailHeight, in.TailHeightAtOewFt},
+		{InputMainGearWidth, in.MainGearWidthFt},
+		{InputCockpitToMainGear, in.CockpitToMainGearFt},
+	} {
+		if input.value != nil && *input.value < 0 {
+			return Classification{}, fmt.Errorf("%s must not be negative", input.name)
+		}
+	}
+
+	if in.ApproachSpeedKnot != nil {
+		c.AAC = &Result{Group: AAC(*in.ApproachSpeedKnot), DrivenBy: InputApproachSpeed}
+	}
+
+	if in.WingspanFt != nil || in.TailHeightAtOewFt != nil {
+		adg, err := ADG(in.WingspanFt, in.TailHeightAtOewFt)
+		if err != nil {
+			return Classification{}, err
+		}
+		c.ADG = &adg
+	}
+
+	if in.MainGearWidthFt != nil && in.CockpitToMainGearFt != nil {
+		tdg := TDG(*in.MainGearWidthFt, *in.CockpitToMainGearFt)
+		c.TDG = &tdg
+	}
+
+	return c, nil
+}
+
+// aacLimits are the exclusive upper approach speeds in knots of categories
+// A to D; faster aircraft are category E
+var aacLimits = []struct {
+	Group string
+	Below float64
+}{
+	{"A", 91},
+	{"B", 121},
+	{"C", 141},
+	{"D", 166},
+}
+
+// AAC returns the approach category for an approach speed in knots
+func AAC(speed float64) string {
+	for _, limit := range aacLimits {
+		if speed < limit.Below {
+			return limit.Group
+		}
+	}
+	return "E"
+}
+
+// AACSpeedLimit returns the exclusive upper approach speed in knots of an
+// approach category; category E has no upper limit
+func AACSpeedLimit(group string) (float64, bool) {
+	for _, limit := range aacLimits {
+		if strings.EqualFold(limit.Group, group) {
+			return limit.Below, true
+		}
+	}
+	if strings.EqualFold(group, "E") {
+		return math.Inf(1), true
+	}
+	return 0, false
+}
+
+// adgLimits are the exclusive upper tail heights and wingspans in feet of
+// design groups I to VI
+var adgLimits = []struct {
+	Group      string
+	TailHeight float64
+	Wingspan   float64
+}{
+	{"I", 20, 49},
+	{"II", 30, 79},
+	{"III", 45, 118},
+	{"IV", 60, 171},
+	{"V", 66, 214},
+	{"VI", 80, 262},
+}
+
+// ADG returns the design group for a wingspan and tail height in feet,
+// either of which may be nil. When they fall into different groups the
+// more demanding one applies.
+func ADG(wingspan, tailHeight *float64) (Result, error) {
+	span, tail := -1, -1
+	if wingspan != nil {
+		span = adgIndex(*wingspan, func(i int) float64 { return adgLimits[i].Wingspan })
+		if span < 0 {
+			return Result{}, fmt.Errorf("wingspan of %g ft exceeds design group VI", *wingspan)
+		}
+	}
+	if tailHeight != nil {
+		tail = adgIndex(*tailHeight, func(i int) float64 { return adgLimits[i].TailHeight })
+		if tail < 0 {
+			return Result{}, fmt.Errorf("tail height of %g ft exceeds design group VI", *tailHeight)
+		}
+	}
+
+	if tail > span {
+		return Result{Group: adgLimits[tail].Group, DrivenBy: InputTailHeight}, nil
+	}
+	return Result{Group: adgLimits[span].Group, DrivenBy: InputWingspan}, nil
+}
+
+// ADGLimits returns the exclusive upper wingspan and tail height in feet
+// of a design group given as a roman numeral
+func ADGLimits(group string) (wingspan, tailHeight float64, ok bool) {
+	for _, limit := range adgLimits {
+		if strings.EqualFold(limit.Group, group) {
+			return limit.Wingspan, limit.TailHeight, true
+		}
+	}
+	return 0, 0, false
+}
+
+// adgIndex returns the index of the first group whose limit exceeds v,
+// or -1 when v is beyond every group
+func adgIndex(v float64, limit func(int) float64) int {
+	for i := range adgLimits {
+		if v < limit(i) {
+			return i
+		}
+	}
+	return -1
+}
+
+// TDGLimits describes the aircraft a taxiway design group accommodates:
+// the exclusive upper main gear width (MGW) and cockpit to main gear
+// distance (CMG) in feet, plus the standard taxiway width and taxiway edge
+// safety margin (TESM) of the group
+type TDGLimits struct {
+	Group         string
+	MainGearWidth float64
+	CockpitToGear float64
+	TaxiwayWidth  float64
+	EdgeMargin    float64
+}
+
+// tdgLimits lists the taxiway design groups from smallest to largest, as
+// read from the TDG chart
+var tdgLimits = []TDGLimits{
+	{"1A", 15, 20, 25, 5},
+	{"1B", 15, 40, 25, 5},
+	{"2A", 20, 40, 35, 7.5},
+	{"2B", 20, 68, 35, 7.5},
+	{"3", 30, 68, 50, 10},
+	{"4", 30, math.Inf(1), 50, 10},
+	{"5", math.Inf(1), 100, 75, 14},
+	{"6", math.Inf(1), math.Inf(1), 75, 14},
+}
+
+// LookupTDG returns the limits of a taxiway design group
+func LookupTDG(group string) (TDGLimits, bool) {
+	for _, limit := range tdgLimits {
+		if strings.EqualFold(limit.Group, group) {
+			return limit, true
+		}
+	}
+	return TDGLimits{}, false
+}
+
+// TDG returns the taxiway design group for a main gear width and cockpit
+// to main gear distance in feet: the smallest group accommodating both.
+// The driver is the dimension ruling out the next smaller group.
+func TDG(mgw, cmg float64) Result {
+	for i, limit := range tdgLimits {
+		if mgw >= limit.MainGearWidth || cmg >= limit.CockpitToGear {
+			continue
+		}
+
+		driver := InputMainGearWidth
+		if i > 0 && cmg >= tdgLimits[i-1].CockpitToGear {
+			driver = InputCockpitToMainGear
+		}
+		return Result{Group: limit.Group, DrivenBy: driver}
+	}
+
+	// Unreachable as group 6 has no limits
+	return Result{Group: tdgLimits[len(tdgLimits)-1].Group, DrivenBy: InputCockpitToMainGear}
+}
+
+// FromAircraft reads the classification inputs of a stored aircraft. The
+// wingspan with winglets or sharklets is used when known.
+func FromAircraft(a db.AircraftDatum) Input {
+	in := Input{
+		ApproachSpeedKnot:   intValue(a.ApproachSpeedKnot),
+		WingspanFt:          units.Decimal(a.WingspanFtWithWingletsSharklets),
+		TailHeightAtOewFt:   units.Decimal(a.TailHeightAtOewFt),
+		MainGearWidthFt:     units.Decimal(a.MainGearWidthFt),
+		CockpitToMainGearFt: units.Decimal(a.CockpitToMainGearFt),
+	}
+	if in.WingspanFt == nil {
+		in.WingspanFt = units.Decimal(a.WingspanFtWithoutWingletsSharklets)
+	}
+	return in
+}
+
+// Mismatch is a stored group that disagrees with the computed one
+type Mismatch struct {
+	Field    string `json:"field"`
+	Stored   string `json:"stored"`
+	Computed string `json:"computed"`
+	DrivenBy string `json:"driven_by"`
+}
+
+// Check compares the stored AAC, ADG and TDG of an aircraft with the
+// groups computed from its dimensions. Groups that are not stored or
+// cannot be computed are skipped.
+func Check(a db.AircraftDatum) ([]Mismatch, error) {
+	c, err := Classify(FromAircraft(a))
+	if err != nil {
+		return nil, err
+	}
+
+	var mismatches []Mismatch
+	for _, check := range []struct {
+		field  string
+		stored pgtype.Text
+		result *Result
+	}{
+		{"aac", a.Aac, c.AAC},
+		{"adg", a.Adg, c.ADG},
+		{"tdg", a.Tdg, c.TDG},
+	} {
+		stored := strings.TrimSpace(check.stored.String)
+		if !check.stored.Valid || stored == "" || check.result == nil {
+			continue
+		}
+		if !strings.EqualFold(stored, check.result.Group) {
+			mismatches = append(mismatches, Mismatch{
+				Field:    check.field,
+				Stored:   stored,
+				Computed: check.result.Group,
+				DrivenBy: check.result.DrivenBy,
+			})
+		}
+	}
+
+	return mismatches, nil
+}
+
+func intValue(v pgtype.Int4) *float64 {
+	if !v.Valid {
+		return nil
+	}
+	f := float64(v.Int32)
+	return &f
+}
@@ -0,0 +1,216 @@
+// Package compare lines up the attributes of several aircraft side by side
+// for gate and runway planning.
+package compare
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+const (
+	// MinAircraft is the fewest aircraft a comparison accepts
+	MinAircraft = 2
+	// MaxAircraft is the most aircraft a comparison accepts
+	MaxAircraft = 6
+)
+
+// Field is a compared aircraft attribute. Exactly one of text and number
+// is set. For numeric fields marked Limiting, the largest value is the one
+// that constrains planning, e.g. the widest wingspan at a gate. Units come
+// from units.Fields.
+type Field struct {
+	Name     string
+	Label    string
+	Limiting bool
+
+	text   func(db.AircraftDatum) pgtype.Text
+	number func(db.AircraftDatum) (float64, bool)
+}
+
+// Fields lists the compared attributes in display order
+var Fields = []Field{
+	{Name: "icao_code", Label: "ICAO Code", text: func(a db.AircraftDatum) pgtype.Text { return a.IcaoCode }},
+	{Name: "manufacturer", Label: "Manufacturer", text: func(a db.AircraftDatum) pgtype.Text { return a.Manufacturer }},
+	{Name: "physical_class_engine", Label: "Physical Class", text: func(a db.AircraftDatum) pgtype.Text { return a.PhysicalClassEngine }},
+	{Name: "num_engines", Label: "Number of Engines", number: func(a db.AircraftDatum) (float64, bool) { return intValue(a.NumEngines) }},
+	{Name: "aac", Label: "AAC", text: func(a db.AircraftDatum) pgtype.Text { return a.Aac }},
+	{Name: "adg", Label: "ADG", text: func(a db.AircraftDatum) pgtype.Text { return a.Adg }},
+	{Name: "tdg", Label: "TDG", text: func(a db.AircraftDatum) pgtype.Text { return a.Tdg }},
+	{Name: "approach_speed_knot", Label: "Approach Speed", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return intValue(a.ApproachSpeedKnot) }},
+	{Name: "wingspan_ft_with_winglets_sharklets", Label: "Wingspan (with winglets)", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.WingspanFtWithWingletsSharklets) }},
+	{Name: "wingspan_ft_without_winglets_sharklets", Label: "Wingspan (without winglets)", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.WingspanFtWithoutWingletsSharklets) }},
+	{Name: "length_ft", Label: "Length", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.LengthFt) }},
+	{Name: "tail_height_at_oew_ft", Label: "Tail Height", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.TailHeightAtOewFt) }},
+	{Name: "wheelbase_ft", Label: "Wheelbase", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.WheelbaseFt) }},
+	{Name: "cockpit_to_main_gear_ft", Label: "Cockpit to Main Gear", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.CockpitToMainGearFt) }},
+	{Name: "main_gear_width_ft", Label: "Main Gear Width", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.MainGearWidthFt) }},
+	{Name: "mtow_lb", Label: "MTOW", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return intValue(a.MtowLb) }},
+	{Name: "malw_lb", Label: "MALW", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return intValue(a.MalwLb) }},
+	{Name: "parking_area_ft2", Label: "Parking Area", Limiting: true, number: func(a db.AircraftDatum) (float64, bool) { return decimalValue(a.ParkingAreaFt2) }},
+	{Name: "main_gear_config", Label: "Main Gear Config", text: func(a db.AircraftDatum) pgtype.Text { return a.MainGearConfig }},
+	{Name: "icao_wtc", Label: "ICAO Wake Turbulence", text: func(a db.AircraftDatum) pgtype.Text { return a.IcaoWtc }},
+	{Name: "faa_weight", Label: "FAA Weight Category", text: func(a db.AircraftDatum) pgtype.Text { return a.FaaWeight }},
+	{Name: "cwt", Label: "CWT", text: func(a db.AircraftDatum) pgtype.Text { return a.Cwt }},
+	{Name: "srs", Label: "SRS", text: func(a db.AircraftDatum) pgtype.Text { return a.Srs }},
+	{Name: "lahso", Label: "LAHSO Capable", text: func(a db.AircraftDatum) pgtype.Text { return a.Lahso }},
+	{Name: "registration_count", Label: "Registration Count", number: func(a db.AircraftDatum) (float64, bool) { return intValue(a.RegistrationCount) }},
+}
+
+// Attribute is one row of a comparison. Values are aligned with the
+// compared aircraft and hold a string, a float64 or nil when the aircraft
+// has no value. Min, Max and Delta are only set for numeric attributes
+// with at least one value; Limiting holds the indexes of the aircraft with
+// the constraining value.
+type Attribute struct {
+	Field    string   `json:"field"`
+	Label    string   `json:"label"`
+	Unit     string   `json:"unit,omitempty"`
+	Values   []any    `json:"values"`
+	Min      *float64 `json:"min,omitempty"`
+	Max      *float64 `json:"max,omitempty"`
+	Delta    *float64 `json:"delta,omitempty"`
+	Limiting []int    `json:"limiting,omitempty"`
+	Same     bool     `json:"same"`
+}
+
+// Comparison holds the compared aircraft and their aligned attributes
+type Comparison struct {
+	Aircraft   []units.Aircraft `json:"aircraft"`
+	Attributes []Attribute      `json:"attributes"`
+}
+
+// New compares the given aircraft in the order given, in the unit system
+func New(rows []db.AircraftDatum, system units.System) Comparison {
+	comparison := Comparison{
+		Aircraft:   units.NewAircraftList(rows, system),
+		Attributes: make([]Attribute, 0, len(Fields)),
+	}
+
+	aircraft := make([]db.AircraftDatum, len(rows))
+	for i, a := range comparison.Aircraft {
+		aircraft[i] = a.AircraftDatum
+	}
+
+	for _, field := range Fields {
+		attr := Attribute{
+			Field:  units.Key(field.Name, system),
+			Label:  field.Label,
+			Values: make([]any, len(aircraft)),
+		}
+		if q, ok := units.Fields[field.Name]; ok {
+			attr.Unit = q.Unit(system)
+		}
+
+		if field.number != nil {
+			compareNumbers(&attr, field, aircraft)
+		} else {
+			for i, a := range aircraft {
+				if v := field.text(a); v.Valid && v.String != "" {
+					attr.Values[i] = v.String
+				}
+			}
+		}
+
+		attr.Same = same(attr.Values)
+		comparison.Attributes = append(comparison.Attributes, attr)
+	}
+
+	return comparison
+}
+
+// compareNumbers fills in the values and statistics of a numeric attribute
+func compareNumbers(attr *Attribute, field Field, aircraft []db.AircraftDatum) {
+	for i, a := range aircraft {
+		v, ok := field.number(a)
+		if !ok {
+			continue
+		}
+		attr.Values[i] = v
+
+		if attr.Min == nil || v < *attr.Min {
+			attr.Min = &v
+		}
+		if attr.Max == nil || v > *attr.Max {
+			attr.Max = &v
+		}
+	}
+
+	if attr.Min == nil {
+		return
+	}
+
+	delta := *attr.Max - *attr.Min
+	attr.Delta = &delta
+
+	// Only highlight a limiting value when the aircraft actually differ
+	if field.Limiting && delta > 0 {
+		for i, v := range attr.Values {
+			if v == *attr.Max {
+				attr.Limiting = append(attr.Limiting, i)
+			}
+		}
+	}
+}
+
+// same reports whether all values are equal
+func same(values []any) bool {
+	if len(values) == 0 {
+		return true
+	}
+	for _, v := range values[1:] {
+		if v != values[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// ParseIDs reads the aircraft ids to compare from a query parameter, which
+// may be comma separated or repeated. Duplicates are dropped and the
+// number of distinct ids must be between MinAircraft and MaxAircraft.
+func ParseIDs(params []string) ([]int32, error) {
+	var ids []int32
+	seen := map[int32]bool{}
+
+	for _, param := range params {
+		for _, part := range strings.Split(param, ",") {
+			part = strings.TrimSpace(part)
+			if part == "" {
+				continue
+			}
+
+			id, err := strconv.ParseInt(part, 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("invalid aircraft id %q", part)
+			}
+			if !seen[int32(id)] {
+				seen[int32(id)] = true
+				ids = append(ids, int32(id))
+			}
+		}
+	}
+
+	if len(ids) < MinAircraft || len(ids) > MaxAircraft {
+		return nil, fmt.Errorf("between %d and %d aircraft ids are required, got %d", MinAircraft, MaxAircraft, len(ids))
+	}
+
+	return ids, nil
+}
+
+// intValue converts a nullable integer column
+func intValue(v pgtype.Int4) (float64, bool) {
+	return float64(v.Int32), v.Valid
+}
+
+// decimalValue converts a nullable numeric column
+func decimalValue(v pgtype.Numeric) (float64, bool) {
+	if f := units.Decimal(v); f != nil {
+		return *f, true
+	}
+	return 0, false
+}
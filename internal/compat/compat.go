@@ -0,0 +1,303 @@
+// Package compat checks which aircraft fit a runway and taxiway designed to
+// a given Runway Design Code (RDC) and Taxiway Design Group (TDG). The
+// taxiway width and the reported values are in the unit system the caller
+// passes; the design standards themselves are defined in feet and knots.
+package compat
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/classify"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+)
+
+// Status is the outcome of a compatibility check
+type Status string
+
+const (
+	Compatible   Status = "compatible"
+	Marginal     Status = "marginal"
+	Incompatible Status = "incompatible"
+)
+
+// worse reports whether s is a worse outcome than other
+func (s Status) worse(other Status) bool {
+	rank := map[Status]int{Compatible: 0, Marginal: 1, Incompatible: 2}
+	return rank[s] > rank[other]
+}
+
+// DefaultMarginPct is how close, in percent, a dimension may come to a
+// design limit before the aircraft is reported as marginal
+const DefaultMarginPct = 5.0
+
+// Criteria holds the airport design parameters to check against.
+// Every parameter is optional, but at least one must be set.
+type Criteria struct {
+	AAC            string   `json:"aac,omitempty"`
+	ADG            string   `json:"adg,omitempty"`
+	TDG            string   `json:"tdg,omitempty"`
+	TaxiwayWidthFt *float64 `json:"taxiway_width_ft,omitempty"`
+	MarginPct      float64  `json:"margin_pct"`
+}
+
+// RDC returns the runway design code, e.g. "C-III", with "-" standing in
+// for a part that was not given
+func (c Criteria) RDC() string {
+	if c.AAC == "" && c.ADG == "" {
+		return ""
+	}
+	aac, adg := c.AAC, c.ADG
+	if aac == "" {
+		aac = "-"
+	}
+	if adg == "" {
+		adg = "-"
+	}
+	return aac + "-" + adg
+}
+
+// IsEmpty reports whether no design parameter was given
+func (c Criteria) IsEmpty() bool {
+	return c.AAC == "" && c.ADG == "" && c.TDG == "" && c.TaxiwayWidthFt == nil
+}
+
+// ParseCriteria reads design parameters from query parameters. The runway
+// design code may be given whole as rdc=C-III, where a trailing visibility
+// minimum such as C-III-2400 is ignored, or in parts as aac=C&adg=III.
+func ParseCriteria(params url.Values) (Criteria, error) {
+	c := Criteria{
+		AAC:       strings.ToUpper(strings.TrimSpace(params.Get("aac"))),
+		ADG:       strings.ToUpper(strings.TrimSpace(params.Get("adg"))),
+		TDG:       strings.ToUpper(strings.TrimSpace(params.Get("tdg"))),
+		MarginPct: DefaultMarginPct,
+	}
+
+	if rdc := strings.TrimSpace(params.Get("rdc")); rdc != "" {
+		parts := strings.Split(strings.ToUpper(rdc), "-")
+		if len(parts) < 2 {
+			return Criteria{}, fmt.Errorf("invalid rdc %q: must look like C-III", rdc)
+		}
+		c.AAC, c.ADG = parts[0], parts[1]
+	}
+
+	if c.AAC != "" {
+		if _, ok := classify.AACSpeedLimit(c.AAC); !ok {
+			return Criteria{}, fmt.Errorf("invalid aircraft approach category %q: must be A to E", c.AAC)
+		}
+	}
+	if c.ADG != "" {
+		if _, _, ok := classify.ADGLimits(c.ADG); !ok {
+			return Criteria{}, fmt.Errorf("invalid airplane design group %q: must be I to VI", c.ADG)
+		}
+	}
+	if c.TDG != "" {
+		if _, ok := classify.LookupTDG(c.TDG); !ok {
+			return Criteria{}, fmt.Errorf("invalid taxiway design group %q: must be 1A, 1B, 2A, 2B or 3 to 6", c.TDG)
+		}
+	}
+
+	if raw := strings.TrimSpace(params.Get("taxiway_width_ft")); raw != "" {
+		width, err := strconv.ParseFloat(raw, 64)
+		if err != nil || width <= 0 || math.IsInf(width, 0) {
+			return Criteria{}, fmt.Errorf("invalid taxiway_width_ft %q: must be a positive number", raw)
+		}
+		c.TaxiwayWidthFt = &width
+	}
+
+	if raw := strings.TrimSpace(params.Get("margin_pct")); raw != "" {
+		margin, err := strconv.ParseFloat(raw, 64)
+		if err != nil || margin < 0 || margin > 50 {
+			return Criteria{}, fmt.Errorf("invalid margin_pct %q: must be between 0 and 50", raw)
+		}
+		c.MarginPct = margin
+	}
+
+	if c.IsEmpty() {
+		return Criteria{}, fmt.Errorf("at least one of rdc, aac, adg, tdg or taxiway_width_ft is required")
+	}
+
+	return c, nil
+}
+
+// Issue explains why an attribute of an aircraft makes it marginal or
+// incompatible. Value is nil when the attribute is unknown. The attribute
+// is named with units.Name, and Unit is the unit of Value and Limit.
+type Issue struct {
+	Attribute string   `json:"attribute"`
+	Status    Status   `json:"status"`
+	Value     *float64 `json:"value,omitempty"`
+	Limit     float64  `json:"limit"`
+	Unit      string   `json:"unit"`
+	Message   string   `json:"message"`
+}
+
+// Check compares an aircraft with the design criteria in the system.
+// Aircraft missing a dimension that is needed are reported as marginal,
+// since they cannot be confirmed to fit.
+func Check(c Criteria, a db.AircraftDatum, s units.System) (Status, []Issue) {
+	in := classify.FromAircraft(a)
+	margin := c.MarginPct / 100
+
+	var issues []Issue
+	limit := func(attribute, label string, q units.Quantity, value *float64, max float64, standard string) {
+		if math.IsInf(max, 1) {
+			return
+		}
+
+		unit := q.Unit(s)
+		issue := Issue{Attribute: units.Name(attribute, s), Value: convert(value, q, s), Limit: q.Convert(max, s), Unit: unit}
+		switch {
+		case value == nil:
+			issue.Status = Marginal
+			issue.Message = fmt.Sprintf("%s is unknown, %s allows less than %s %s", label, standard, format(issue.Limit), unit)
+		case *value >= max:
+			issue.Status = Incompatible
+			issue.Message = fmt.Sprintf("%s of %s %s exceeds %s, which allows less than %s %s", label, format(*issue.Value), unit, standard, format(issue.Limit), unit)
+		case *value >= max*(1-margin):
+			issue.Status = Marginal
+			issue.Message = fmt.Sprintf("%s of %s %s is within %s%% of the %s limit of %s %s", label, format(*issue.Value), unit, format(c.MarginPct), standard, format(issue.Limit), unit)
+		default:
+			return
+		}
+		issues = append(issues, issue)
+	}
+
+	if c.AAC != "" {
+		speed, _ := classify.AACSpeedLimit(c.AAC)
+		limit(classify.InputApproachSpeed, "approach speed", units.Speed, in.ApproachSpeedKnot, speed, "AAC "+c.AAC)
+	}
+
+	if c.ADG != "" {
+		wingspan, tail, _ := classify.ADGLimits(c.ADG)
+		limit(classify.InputWingspan, "wingspan", units.Length, in.WingspanFt, wingspan, "ADG "+c.ADG)
+		limit(classify.InputTailHeight, "tail height", units.Length, in.TailHeightAtOewFt, tail, "ADG "+c.ADG)
+	}
+
+	// The design group sets the taxiway edge safety margin; without one the
+	// aircraft's own group is used
+	var tdg classify.TDGLimits
+	if c.TDG != "" {
+		tdg, _ = classify.LookupTDG(c.TDG)
+		limit(classify.InputMainGearWidth, "main gear width", units.Length, in.MainGearWidthFt, tdg.MainGearWidth, "TDG "+c.TDG)
+		limit(classify.InputCockpitToMainGear, "cockpit to main gear distance", units.Length, in.CockpitToMainGearFt, tdg.CockpitToGear, "TDG "+c.TDG)
+	} else if in.MainGearWidthFt != nil {
+		cmg := 0.0
+		if in.CockpitToMainGearFt != nil {
+			cmg = *in.CockpitToMainGearFt
+		}
+		tdg, _ = classify.LookupTDG(classify.TDG(*in.MainGearWidthFt, cmg).Group)
+	}
+
+	if c.TaxiwayWidthFt != nil {
+		issues = append(issues, taxiwayWidth(*c.TaxiwayWidthFt, in.MainGearWidthFt, tdg, s)...)
+	}
+
+	status := Compatible
+	for _, issue := range issues {
+		if issue.Status.worse(status) {
+			status = issue.Status
+		}
+	}
+	return status, issues
+}
+
+// taxiwayWidth checks that the main gear fits the taxiway with the taxiway
+// edge safety margin (TESM) on both sides. The width is given in the
+// system and compared in feet.
+func taxiwayWidth(width float64, mgw *float64, tdg classify.TDGLimits, s units.System) []Issue {
+	widthFt, unit := units.Length.ToImperial(width, s), units.Length.Unit(s)
+	issue := Issue{Attribute: units.Name(classify.InputMainGearWidth, s), Value: convert(mgw, units.Length, s), Limit: width, Unit: unit}
+
+	switch {
+	case mgw == nil:
+		issue.Status = Marginal
+		issue.Message = fmt.Sprintf("main gear width is unknown, cannot check the %s %s taxiway width", format(width), unit)
+	case *mgw >= widthFt:
+		issue.Status = Incompatible
+		issue.Message = fmt.Sprintf("main gear width of %s %s does not fit the %s %s taxiway width", format(*issue.Value), unit, format(width), unit)
+	case *mgw+2*tdg.EdgeMargin > widthFt:
+		issue.Status = Marginal
+		issue.Message = fmt.Sprintf("main gear width of %s %s leaves less than the %s %s edge safety margin of TDG %s on a %s %s taxiway",
+			format(*issue.Value), unit, format(units.Length.Convert(tdg.EdgeMargin, s)), unit, tdg.Group, format(width), unit)
+	default:
+		return nil
+	}
+
+	return []Issue{issue}
+}
+
+// Entry is an aircraft in a compatibility report
+type Entry struct {
+	ID            int32   `json:"id"`
+	IcaoCode      string  `json:"icao_code"`
+	FaaDesignator string  `json:"faa_designator"`
+	Manufacturer  string  `json:"manufacturer"`
+	ModelFaa      string  `json:"model_faa"`
+	Issues        []Issue `json:"issues,omitempty"`
+}
+
+// Report groups the aircraft of the database by compatibility
+type Report struct {
+	Criteria     Criteria `json:"criteria"`
+	RDC          string   `json:"rdc,omitempty"`
+	Compatible   []Entry  `json:"compatible"`
+	Marginal     []Entry  `json:"marginal"`
+	Incompatible []Entry  `json:"incompatible"`
+}
+
+// Evaluate checks every aircraft in the database against the criteria in
+// the system, in the default manufacturer and model order
+func Evaluate(ctx context.Context, conn db.DBTX, c Criteria, s units.System) (Report, error) {
+	report := Report{
+		Criteria:     c,
+		RDC:          c.RDC(),
+		Compatible:   []Entry{},
+		Marginal:     []Entry{},
+		Incompatible: []Entry{},
+	}
+
+	err := search.Each(ctx, conn, search.Query{}, func(a db.AircraftDatum) error {
+		status, issues := Check(c, a, s)
+		entry := Entry{
+			ID:            a.ID,
+			IcaoCode:      a.IcaoCode.String,
+			FaaDesignator: a.FaaDesignator.String,
+			Manufacturer:  a.Manufacturer.String,
+			ModelFaa:      a.ModelFaa.String,
+			Issues:        issues,
+		}
+
+		switch status {
+		case Incompatible:
+			report.Incompatible = append(report.Incompatible, entry)
+		case Marginal:
+			report.Marginal = append(report.Marginal, entry)
+		default:
+			report.Compatible = append(report.Compatible, entry)
+		}
+		return nil
+	})
+
+	return report, err
+}
+
+// convert converts an optional imperial value to the system
+func convert(v *float64, q units.Quantity, s units.System) *float64 {
+	if v == nil {
+		return nil
+	}
+	f := q.Convert(*v, s)
+	return &f
+}
+
+// format renders a number without trailing zeros
+func format(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
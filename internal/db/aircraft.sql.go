@@ -22,22 +22,6 @@ func (q *Queries) CountAircraft(ctx context.Context) (int64, error) {
 	return count, err
 }
 
-const countSearchAircraft = `-- name: CountSearchAircraft :one
-SELECT COUNT(*) FROM aircraft_data
-WHERE 
-    UPPER(icao_code) LIKE UPPER($1::text) OR 
-    UPPER(faa_designator) LIKE UPPER($1::text) OR 
-    UPPER(manufacturer) LIKE UPPER($1::text) OR 
-    UPPER(model_faa) LIKE UPPER($1::text)
-`
-
-func (q *Queries) CountSearchAircraft(ctx context.Context, searchTerm string) (int64, error) {
-	row := q.db.QueryRow(ctx, countSearchAircraft, searchTerm)
-	var count int64
-	err := row.Scan(&count)
-	return count, err
-}
-
 const createAircraftData = `-- name: CreateAircraftData :one
 INSERT INTO aircraft_data (
     icao_code, faa_designator, manufacturer, model_faa, model_bada,
@@ -195,6 +179,19 @@ func (q *Queries) CreateAircraftData(ctx context.Context, arg CreateAircraftData
 	return i, err
 }
 
+const deleteAircraftDataExcept = `-- name: DeleteAircraftDataExcept :execrows
+DELETE FROM aircraft_data
+WHERE NOT (id = ANY($1::int[]))
+`
+
+func (q *Queries) DeleteAircraftDataExcept(ctx context.Context, ids []int32) (int64, error) {
+	result, err := q.db.Exec(ctx, deleteAircraftDataExcept, ids)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected(), nil
+}
+
 const deleteAllAircraftData = `-- name: DeleteAllAircraftData :exec
 DELETE FROM aircraft_data
 `
@@ -261,19 +258,22 @@ func (q *Queries) GetAircraft(ctx context.Context, id int32) (AircraftDatum, err
 	return i, err
 }
 
-const getAllAircraft = `-- name: GetAllAircraft :many
+const getAircraftBatch = `-- name: GetAircraftBatch :many
 SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at FROM aircraft_data
-ORDER BY manufacturer, model_faa
-LIMIT $1 OFFSET $2
+WHERE UPPER(icao_code) = ANY($1::text[])
+    OR UPPER(faa_designator) = ANY($2::text[])
+    OR id = ANY($3::int[])
+ORDER BY icao_code, faa_designator, id
 `
 
-type GetAllAircraftParams struct {
-	Limit  int32 `json:"limit"`
-	Offset int32 `json:"offset"`
+type GetAircraftBatchParams struct {
+	IcaoCodes      []string `json:"icao_codes"`
+	FaaDesignators []string `json:"faa_designators"`
+	Ids            []int32  `json:"ids"`
 }
 
-func (q *Queries) GetAllAircraft(ctx context.Context, arg GetAllAircraftParams) ([]AircraftDatum, error) {
-	rows, err := q.db.Query(ctx, getAllAircraft, arg.Limit, arg.Offset)
+func (q *Queries) GetAircraftBatch(ctx context.Context, arg GetAircraftBatchParams) ([]AircraftDatum, error) {
+	rows, err := q.db.Query(ctx, getAircraftBatch, arg.IcaoCodes, arg.FaaDesignators, arg.Ids)
 	if err != nil {
 		return nil, err
 	}
@@ -337,25 +337,161 @@ func (q *Queries) GetAllAircraft(ctx context.Context, arg GetAllAircraftParams)
 	return items, nil
 }
 
-const searchAircraft = `-- name: SearchAircraft :many
+const getAircraftByFAADesignator = `-- name: GetAircraftByFAADesignator :many
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at FROM aircraft_data
+WHERE UPPER(faa_designator) = UPPER($1::text)
+ORDER BY icao_code, id
+`
+
+func (q *Queries) GetAircraftByFAADesignator(ctx context.Context, designator string) ([]AircraftDatum, error) {
+	rows, err := q.db.Query(ctx, getAircraftByFAADesignator, designator)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AircraftDatum{}
+	for rows.Next() {
+		var i AircraftDatum
+		if err := rows.Scan(
+			&i.ID,
+			&i.IcaoCode,
+			&i.FaaDesignator,
+			&i.Manufacturer,
+			&i.ModelFaa,
+			&i.ModelBada,
+			&i.PhysicalClassEngine,
+			&i.NumEngines,
+			&i.Aac,
+			&i.AacMinimum,
+			&i.AacMaximum,
+			&i.Adg,
+			&i.Tdg,
+			&i.ApproachSpeedKnot,
+			&i.ApproachSpeedMinimumKnot,
+			&i.ApproachSpeedMaximumKnot,
+			&i.WingspanFtWithoutWingletsSharklets,
+			&i.WingspanFtWithWingletsSharklets,
+			&i.LengthFt,
+			&i.TailHeightAtOewFt,
+			&i.WheelbaseFt,
+			&i.CockpitToMainGearFt,
+			&i.MainGearWidthFt,
+			&i.MtowLb,
+			&i.MalwLb,
+			&i.MainGearConfig,
+			&i.IcaoWtc,
+			&i.ParkingAreaFt2,
+			&i.Class,
+			&i.FaaWeight,
+			&i.Cwt,
+			&i.OneHalfWakeCategory,
+			&i.TwoWakeCategoryAppxA,
+			&i.TwoWakeCategoryAppxB,
+			&i.RotorDiameterFt,
+			&i.Srs,
+			&i.Lahso,
+			&i.FaaRegistry,
+			&i.RegistrationCount,
+			&i.TmfsOperationsFy24,
+			&i.Remarks,
+			&i.LastUpdate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAircraftByICAOCode = `-- name: GetAircraftByICAOCode :many
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at FROM aircraft_data
+WHERE UPPER(icao_code) = UPPER($1::text)
+ORDER BY faa_designator, id
+`
+
+func (q *Queries) GetAircraftByICAOCode(ctx context.Context, code string) ([]AircraftDatum, error) {
+	rows, err := q.db.Query(ctx, getAircraftByICAOCode, code)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	items := []AircraftDatum{}
+	for rows.Next() {
+		var i AircraftDatum
+		if err := rows.Scan(
+			&i.ID,
+			&i.IcaoCode,
+			&i.FaaDesignator,
+			&i.Manufacturer,
+			&i.ModelFaa,
+			&i.ModelBada,
+			&i.PhysicalClassEngine,
+			&i.NumEngines,
+			&i.Aac,
+			&i.AacMinimum,
+			&i.AacMaximum,
+			&i.Adg,
+			&i.Tdg,
+			&i.ApproachSpeedKnot,
+			&i.ApproachSpeedMinimumKnot,
+			&i.ApproachSpeedMaximumKnot,
+			&i.WingspanFtWithoutWingletsSharklets,
+			&i.WingspanFtWithWingletsSharklets,
+			&i.LengthFt,
+			&i.TailHeightAtOewFt,
+			&i.WheelbaseFt,
+			&i.CockpitToMainGearFt,
+			&i.MainGearWidthFt,
+			&i.MtowLb,
+			&i.MalwLb,
+			&i.MainGearConfig,
+			&i.IcaoWtc,
+			&i.ParkingAreaFt2,
+			&i.Class,
+			&i.FaaWeight,
+			&i.Cwt,
+			&i.OneHalfWakeCategory,
+			&i.TwoWakeCategoryAppxA,
+			&i.TwoWakeCategoryAppxB,
+			&i.RotorDiameterFt,
+			&i.Srs,
+			&i.Lahso,
+			&i.FaaRegistry,
+			&i.RegistrationCount,
+			&i.TmfsOperationsFy24,
+			&i.Remarks,
+			&i.LastUpdate,
+			&i.CreatedAt,
+			&i.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+		items = append(items, i)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return items, nil
+}
+
+const getAllAircraft = `-- name: GetAllAircraft :many
 SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at FROM aircraft_data
-WHERE 
-    UPPER(icao_code) LIKE UPPER($3::text) OR 
-    UPPER(faa_designator) LIKE UPPER($3::text) OR 
-    UPPER(manufacturer) LIKE UPPER($3::text) OR 
-    UPPER(model_faa) LIKE UPPER($3::text)
 ORDER BY manufacturer, model_faa
 LIMIT $1 OFFSET $2
 `
 
-type SearchAircraftParams struct {
-	Limit      int32  `json:"limit"`
-	Offset     int32  `json:"offset"`
-	SearchTerm string `json:"search_term"`
+type GetAllAircraftParams struct {
+	Limit  int32 `json:"limit"`
+	Offset int32 `json:"offset"`
 }
 
-func (q *Queries) SearchAircraft(ctx context.Context, arg SearchAircraftParams) ([]AircraftDatum, error) {
-	rows, err := q.db.Query(ctx, searchAircraft, arg.Limit, arg.Offset, arg.SearchTerm)
+func (q *Queries) GetAllAircraft(ctx context.Context, arg GetAllAircraftParams) ([]AircraftDatum, error) {
+	rows, err := q.db.Query(ctx, getAllAircraft, arg.Limit, arg.Offset)
 	if err != nil {
 		return nil, err
 	}
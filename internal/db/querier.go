@@ -10,12 +10,23 @@ import (
 
 type Querier interface {
 	CountAircraft(ctx context.Context) (int64, error)
-	CountSearchAircraft(ctx context.Context, searchTerm string) (int64, error)
 	CreateAircraftData(ctx context.Context, arg CreateAircraftDataParams) (AircraftDatum, error)
+	CreateDatasetVersion(ctx context.Context, arg CreateDatasetVersionParams) (DatasetVersion, error)
+	DeleteAircraftDataExcept(ctx context.Context, ids []int32) (int64, error)
 	DeleteAllAircraftData(ctx context.Context) error
 	GetAircraft(ctx context.Context, id int32) (AircraftDatum, error)
+	GetAircraftAtVersion(ctx context.Context, arg GetAircraftAtVersionParams) (GetAircraftAtVersionRow, error)
+	GetAircraftBatch(ctx context.Context, arg GetAircraftBatchParams) ([]AircraftDatum, error)
+	GetAircraftByFAADesignator(ctx context.Context, designator string) ([]AircraftDatum, error)
+	GetAircraftByFAADesignatorAtVersion(ctx context.Context, arg GetAircraftByFAADesignatorAtVersionParams) ([]GetAircraftByFAADesignatorAtVersionRow, error)
+	GetAircraftByICAOCode(ctx context.Context, code string) ([]AircraftDatum, error)
+	GetAircraftByICAOCodeAtVersion(ctx context.Context, arg GetAircraftByICAOCodeAtVersionParams) ([]GetAircraftByICAOCodeAtVersionRow, error)
 	GetAllAircraft(ctx context.Context, arg GetAllAircraftParams) ([]AircraftDatum, error)
-	SearchAircraft(ctx context.Context, arg SearchAircraftParams) ([]AircraftDatum, error)
+	GetDatasetVersion(ctx context.Context, id int32) (DatasetVersion, error)
+	ListDatasetVersions(ctx context.Context) ([]DatasetVersion, error)
+	MarkDatasetVersionRolledBack(ctx context.Context, id int32) error
+	RestoreAircraftData(ctx context.Context, versionID int32) (int64, error)
+	SnapshotAircraftData(ctx context.Context, versionID int32) (int64, error)
 	UpsertAircraftData(ctx context.Context, arg UpsertAircraftDataParams) (AircraftDatum, error)
 }
 
@@ -2,16 +2,6 @@
 SELECT * FROM aircraft_data
 WHERE id = $1 LIMIT 1;
 
--- name: SearchAircraft :many
-SELECT * FROM aircraft_data
-WHERE 
-    UPPER(icao_code) LIKE UPPER(@search_term::text) OR 
-    UPPER(faa_designator) LIKE UPPER(@search_term::text) OR 
-    UPPER(manufacturer) LIKE UPPER(@search_term::text) OR 
-    UPPER(model_faa) LIKE UPPER(@search_term::text)
-ORDER BY manufacturer, model_faa
-LIMIT $1 OFFSET $2;
-
 -- name: CountAircraft :one
 SELECT COUNT(*) FROM aircraft_data;
 
@@ -20,14 +10,6 @@ SELECT * FROM aircraft_data
 ORDER BY manufacturer, model_faa
 LIMIT $1 OFFSET $2;
 
--- name: CountSearchAircraft :one
-SELECT COUNT(*) FROM aircraft_data
-WHERE 
-    UPPER(icao_code) LIKE UPPER(@search_term::text) OR 
-    UPPER(faa_designator) LIKE UPPER(@search_term::text) OR 
-    UPPER(manufacturer) LIKE UPPER(@search_term::text) OR 
-    UPPER(model_faa) LIKE UPPER(@search_term::text);
-
 -- name: CreateAircraftData :one
 INSERT INTO aircraft_data (
     icao_code, faa_designator, manufacturer, model_faa, model_bada,
@@ -107,4 +89,25 @@ ON CONFLICT (icao_code, faa_designator) DO UPDATE SET
 RETURNING *;
 
 -- name: DeleteAllAircraftData :exec
-DELETE FROM aircraft_data;
\ No newline at end of file
+DELETE FROM aircraft_data;
+
+-- name: DeleteAircraftDataExcept :execrows
+DELETE FROM aircraft_data
+WHERE NOT (id = ANY(@ids::int[]));
+
+-- name: GetAircraftByICAOCode :many
+SELECT * FROM aircraft_data
+WHERE UPPER(icao_code) = UPPER(@code::text)
+ORDER BY faa_designator, id;
+
+-- name: GetAircraftByFAADesignator :many
+SELECT * FROM aircraft_data
+WHERE UPPER(faa_designator) = UPPER(@designator::text)
+ORDER BY icao_code, id;
+
+-- name: GetAircraftBatch :many
+SELECT * FROM aircraft_data
+WHERE UPPER(icao_code) = ANY(@icao_codes::text[])
+    OR UPPER(faa_designator) = ANY(@faa_designators::text[])
+    OR id = ANY(@ids::int[])
+ORDER BY icao_code, faa_designator, id;
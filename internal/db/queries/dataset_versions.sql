@@ -0,0 +1,46 @@
+-- name: CreateDatasetVersion :one
+INSERT INTO dataset_versions (
+    source_file, source_sha256, sheet_name, row_count
+) VALUES (
+    $1, $2, $3, $4
+)
+RETURNING *;
+
+-- name: GetDatasetVersion :one
+SELECT * FROM dataset_versions
+WHERE id = $1;
+
+-- name: ListDatasetVersions :many
+SELECT * FROM dataset_versions
+ORDER BY id DESC;
+
+-- name: SnapshotAircraftData :execrows
+INSERT INTO aircraft_data_history
+SELECT @version_id::int, aircraft_data.* FROM aircraft_data;
+
+-- name: GetAircraftAtVersion :one
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at
+FROM aircraft_data_history
+WHERE version_id = @version_id AND id = @id;
+
+-- name: GetAircraftByICAOCodeAtVersion :many
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at
+FROM aircraft_data_history
+WHERE version_id = @version_id AND UPPER(icao_code) = UPPER(@code::text)
+ORDER BY faa_designator, id;
+
+-- name: GetAircraftByFAADesignatorAtVersion :many
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at
+FROM aircraft_data_history
+WHERE version_id = @version_id AND UPPER(faa_designator) = UPPER(@designator::text)
+ORDER BY icao_code, id;
+
+-- name: MarkDatasetVersionRolledBack :exec
+UPDATE dataset_versions SET rolled_back_at = CURRENT_TIMESTAMP
+WHERE id = $1;
+
+-- name: RestoreAircraftData :execrows
+INSERT INTO aircraft_data (id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at)
+SELECT id, icao_code, faa_designator, manufacturer, model_faa, model_bada, physical_class_engine, num_engines, aac, aac_minimum, aac_maximum, adg, tdg, approach_speed_knot, approach_speed_minimum_knot, approach_speed_maximum_knot, wingspan_ft_without_winglets_sharklets, wingspan_ft_with_winglets_sharklets, length_ft, tail_height_at_oew_ft, wheelbase_ft, cockpit_to_main_gear_ft, main_gear_width_ft, mtow_lb, malw_lb, main_gear_config, icao_wtc, parking_area_ft2, class, faa_weight, cwt, one_half_wake_category, two_wake_category_appx_a, two_wake_category_appx_b, rotor_diameter_ft, srs, lahso, faa_registry, registration_count, tmfs_operations_fy24, remarks, last_update, created_at, updated_at
+FROM aircraft_data_history
+WHERE version_id = @version_id;
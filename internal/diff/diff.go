@@ -0,0 +1,178 @@
+// Package diff compares a release of the FAA Aircraft Characteristics
+// Database with the imported data, so the changes an import would make can
+// be reviewed first.
+package diff
+
+import (
+	"context"
+	"sort"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/export"
+	"github.com/dukerupert/faa-aircraft-search/internal/migration"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+)
+
+// Key identifies an aircraft type across releases. It is the unique key
+// the import upserts on; a missing code is the empty string.
+type Key struct {
+	ICAOCode      string `json:"icao_code"`
+	FAADesignator string `json:"faa_designator"`
+}
+
+func (k Key) String() string {
+	return k.ICAOCode + " / " + k.FAADesignator
+}
+
+// less orders keys by ICAO code, then FAA designator
+func (k Key) less(o Key) bool {
+	if k.ICAOCode != o.ICAOCode {
+		return k.ICAOCode < o.ICAOCode
+	}
+	return k.FAADesignator < o.FAADesignator
+}
+
+// Entry is an added or removed aircraft type
+type Entry struct {
+	Key
+	Manufacturer string `json:"manufacturer,omitempty"`
+	Model        string `json:"model,omitempty"`
+}
+
+// FieldChange is a changed value of a modified type. Field is the column
+// header of the ACD_Data sheet. Values hold a string, an int32, a float64
+// or nil when the value is missing.
+type FieldChange struct {
+	Field string `json:"field"`
+	Old   any    `json:"old"`
+	New   any    `json:"new"`
+}
+
+// Change is an aircraft type present in both releases with differing
+// values
+type Change struct {
+	Entry
+	Fields []FieldChange `json:"fields"`
+}
+
+// Report lists the differences between the current data and an incoming
+// release. Removed types are missing from the release; an import keeps
+// them, since it only upserts. Duplicates counts rows of the release
+// repeating a key, of which the import keeps the last.
+type Report struct {
+	Added      []Entry  `json:"added"`
+	Removed    []Entry  `json:"removed"`
+	Modified   []Change `json:"modified"`
+	Unchanged  int      `json:"unchanged"`
+	Duplicates int      `json:"duplicates"`
+}
+
+// Empty reports whether the release matches the current data
+func (r Report) Empty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Modified) == 0
+}
+
+// Positions of the columns an entry names an aircraft by, in the order of
+// migration.Headers
+const (
+	icaoColumn         = 0
+	faaColumn          = 1
+	manufacturerColumn = 2
+	modelColumn        = 3
+)
+
+// Compare compares the current aircraft with the rows of an incoming
+// release. Every list of the report is sorted by key.
+func Compare(current []db.AircraftDatum, incoming []migration.AircraftData) Report {
+	report := Report{
+		Added:    []Entry{},
+		Removed:  []Entry{},
+		Modified: []Change{},
+	}
+
+	old := make(map[Key][]any, len(current))
+	for _, a := range current {
+		record := export.Record(a)
+		old[keyOf(record)] = record
+	}
+
+	next := make(map[Key][]any, len(incoming))
+	for _, a := range incoming {
+		record := a.Record()
+		key := keyOf(record)
+		if _, ok := next[key]; ok {
+			report.Duplicates++
+		}
+		next[key] = record
+	}
+
+	for key, record := range next {
+		before, ok := old[key]
+		if !ok {
+			report.Added = append(report.Added, entryOf(key, record))
+			continue
+		}
+
+		var fields []FieldChange
+		for i, header := range migration.Headers {
+			if before[i] != record[i] {
+				fields = append(fields, FieldChange{Field: header, Old: before[i], New: record[i]})
+			}
+		}
+		if len(fields) == 0 {
+			report.Unchanged++
+			continue
+		}
+		report.Modified = append(report.Modified, Change{Entry: entryOf(key, record), Fields: fields})
+	}
+
+	for key, record := range old {
+		if _, ok := next[key]; !ok {
+			report.Removed = append(report.Removed, entryOf(key, record))
+		}
+	}
+
+	sortEntries(report.Added)
+	sortEntries(report.Removed)
+	sort.Slice(report.Modified, func(i, j int) bool {
+		return report.Modified[i].Key.less(report.Modified[j].Key)
+	})
+	return report
+}
+
+// CompareCurrent compares an incoming release with the aircraft_data table
+func CompareCurrent(ctx context.Context, conn db.DBTX, incoming []migration.AircraftData) (Report, error) {
+	var current []db.AircraftDatum
+	err := search.Each(ctx, conn, search.Query{}, func(a db.AircraftDatum) error {
+		current = append(current, a)
+		return nil
+	})
+	if err != nil {
+		return Report{}, err
+	}
+	return Compare(current, incoming), nil
+}
+
+func keyOf(record []any) Key {
+	return Key{ICAOCode: str(record[icaoColumn]), FAADesignator: str(record[faaColumn])}
+}
+
+func entryOf(key Key, record []any) Entry {
+	return Entry{
+		Key:          key,
+		Manufacturer: str(record[manufacturerColumn]),
+		Model:        str(record[modelColumn]),
+	}
+}
+
+func sortEntries(entries []Entry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].Key.less(entries[j].Key)
+	})
+}
+
+// str returns a text value of a record, or "" when it is missing
+func str(v any) string {
+	s, _ := v.(string)
+	return s
+}
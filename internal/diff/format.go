@@ -0,0 +1,162 @@
+package diff
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Format is an output format of a report
+type Format string
+
+const (
+	FormatText     Format = "text"
+	FormatJSON     Format = "json"
+	FormatMarkdown Format = "markdown"
+)
+
+// ParseFormat validates a report format name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(name); f {
+	case FormatText, FormatJSON, FormatMarkdown:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported diff format %q: must be text, json or markdown", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	switch f {
+	case FormatJSON:
+		return "application/json; charset=utf-8"
+	case FormatMarkdown:
+		return "text/markdown; charset=utf-8"
+	default:
+		return "text/plain; charset=utf-8"
+	}
+}
+
+// Write renders the report in the format
+func Write(w io.Writer, r Report, format Format) error {
+	switch format {
+	case FormatJSON:
+		enc := json.NewEncoder(w)
+		enc.SetIndent("", "  ")
+		return enc.Encode(r)
+	case FormatMarkdown:
+		return writeMarkdown(w, r)
+	case FormatText:
+		return writeText(w, r)
+	default:
+		return fmt.Errorf("unsupported diff format %q", format)
+	}
+}
+
+// summary is the one line overview shared by the text formats
+func (r Report) summary() string {
+	s := fmt.Sprintf("%d added, %d removed, %d modified, %d unchanged",
+		len(r.Added), len(r.Removed), len(r.Modified), r.Unchanged)
+	if r.Duplicates > 0 {
+		s += fmt.Sprintf(", %d repeated keys in the release", r.Duplicates)
+	}
+	return s
+}
+
+func writeText(w io.Writer, r Report) error {
+	var b strings.Builder
+	b.WriteString(r.summary() + "\n")
+
+	entries := func(title, marker string, list []Entry) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n%s:\n", title)
+		for _, e := range list {
+			fmt.Fprintf(&b, "  %s %s  %s\n", marker, e.Key, e.describe())
+		}
+	}
+	entries("Added", "+", r.Added)
+	entries("Removed", "-", r.Removed)
+
+	if len(r.Modified) > 0 {
+		b.WriteString("\nModified:\n")
+		for _, c := range r.Modified {
+			fmt.Fprintf(&b, "  ~ %s  %s\n", c.Key, c.describe())
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "      %s: %s -> %s\n", f.Field, value(f.Old), value(f.New))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeMarkdown(w io.Writer, r Report) error {
+	var b strings.Builder
+	b.WriteString("# Aircraft data changes\n\n")
+	b.WriteString(r.summary() + "\n")
+
+	entries := func(title string, list []Entry) {
+		if len(list) == 0 {
+			return
+		}
+		fmt.Fprintf(&b, "\n## %s (%d)\n\n", title, len(list))
+		b.WriteString("| ICAO Code | FAA Designator | Manufacturer | Model |\n")
+		b.WriteString("| --- | --- | --- | --- |\n")
+		for _, e := range list {
+			fmt.Fprintf(&b, "| %s | %s | %s | %s |\n",
+				cell(e.ICAOCode), cell(e.FAADesignator), cell(e.Manufacturer), cell(e.Model))
+		}
+	}
+	entries("Added", r.Added)
+	entries("Removed", r.Removed)
+
+	if len(r.Modified) > 0 {
+		fmt.Fprintf(&b, "\n## Modified (%d)\n", len(r.Modified))
+		for _, c := range r.Modified {
+			fmt.Fprintf(&b, "\n### %s\n\n", cell(c.Key.String()))
+			if d := c.describe(); d != "" {
+				b.WriteString(cell(d) + "\n\n")
+			}
+			b.WriteString("| Field | Old | New |\n")
+			b.WriteString("| --- | --- | --- |\n")
+			for _, f := range c.Fields {
+				fmt.Fprintf(&b, "| %s | %s | %s |\n", f.Field, cell(value(f.Old)), cell(value(f.New)))
+			}
+		}
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// describe names the manufacturer and model of an entry
+func (e Entry) describe() string {
+	return strings.TrimSpace(e.Manufacturer + " " + e.Model)
+}
+
+// value formats a record value, marking missing values
+func value(v any) string {
+	switch v := v.(type) {
+	case nil:
+		return "(none)"
+	case string:
+		return strconv.Quote(v)
+	case int32:
+		return strconv.FormatInt(int64(v), 10)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// cell escapes text for a Markdown table cell
+func cell(s string) string {
+	s = strings.ReplaceAll(s, "|", `\|`)
+	return strings.ReplaceAll(s, "\n", " ")
+}
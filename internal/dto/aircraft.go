@@ -0,0 +1,236 @@
+// Package dto defines the stable public JSON representation of aircraft
+// served by the v2 API. Unlike db.AircraftDatum it does not depend on
+// pgtype or on the generated column layout: numbers are plain JSON
+// numbers, missing values are explicit nulls and fields are grouped by
+// topic.
+package dto
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Aircraft is an aircraft type in the v2 API
+type Aircraft struct {
+	ID             int32          `json:"id"`
+	IcaoCode       *string        `json:"icao_code"`
+	FaaDesignator  *string        `json:"faa_designator"`
+	Manufacturer   *string        `json:"manufacturer"`
+	Model          *string        `json:"model"`
+	ModelBada      *string        `json:"model_bada"`
+	Classification Classification `json:"classification"`
+	ApproachSpeed  ApproachSpeed  `json:"approach_speed"`
+	Dimensions     Dimensions     `json:"dimensions"`
+	Weights        Weights        `json:"weights"`
+	Wake           Wake           `json:"wake"`
+	Operations     Operations     `json:"operations"`
+	Remarks        *string        `json:"remarks"`
+	LastUpdate     *string        `json:"last_update"`
+}
+
+// Classification holds the design groups and physical class
+type Classification struct {
+	AircraftClass *string `json:"aircraft_class"`
+	PhysicalClass *string `json:"physical_class"`
+	NumEngines    *int    `json:"num_engines"`
+	AAC           *string `json:"aac"`
+	AACMinimum    *string `json:"aac_minimum"`
+	AACMaximum    *string `json:"aac_maximum"`
+	ADG           *string `json:"adg"`
+	TDG           *string `json:"tdg"`
+}
+
+// ApproachSpeed holds the typical approach speed and its range
+type ApproachSpeed struct {
+	Unit    string `json:"unit"`
+	Typical *int   `json:"typical"`
+	Minimum *int   `json:"minimum"`
+	Maximum *int   `json:"maximum"`
+}
+
+// Dimensions holds the physical dimensions. Lengths are in Unit and the
+// parking area in AreaUnit.
+type Dimensions struct {
+	Unit                    string   `json:"unit"`
+	AreaUnit                string   `json:"area_unit"`
+	WingspanWithoutWinglets *float64 `json:"wingspan_without_winglets"`
+	WingspanWithWinglets    *float64 `json:"wingspan_with_winglets"`
+	Length                  *float64 `json:"length"`
+	TailHeightAtOew         *float64 `json:"tail_height_at_oew"`
+	Wheelbase               *float64 `json:"wheelbase"`
+	CockpitToMainGear       *float64 `json:"cockpit_to_main_gear"`
+	MainGearWidth           *float64 `json:"main_gear_width"`
+	MainGearConfig          *string  `json:"main_gear_config"`
+	RotorDiameter           *float64 `json:"rotor_diameter"`
+	ParkingArea             *float64 `json:"parking_area"`
+}
+
+// Weights holds the certificated weights
+type Weights struct {
+	Unit string `json:"unit"`
+	MTOW *int   `json:"mtow"`
+	MALW *int   `json:"malw"`
+}
+
+// Wake holds the wake turbulence categories of each scheme
+type Wake struct {
+	IcaoWtc         *string `json:"icao_wtc"`
+	FaaWeightClass  *string `json:"faa_weight_class"`
+	CWT             *string `json:"cwt"`
+	Recat15         *string `json:"recat_1_5"`
+	Recat2AppendixA *string `json:"recat_2_appendix_a"`
+	Recat2AppendixB *string `json:"recat_2_appendix_b"`
+}
+
+// Operations holds operational and registry data
+type Operations struct {
+	SRS                *string `json:"srs"`
+	LahsoGroup         *int    `json:"lahso_group"`
+	FaaRegistered      *bool   `json:"faa_registered"`
+	RegistrationCount  *int    `json:"registration_count"`
+	TmfsOperationsFY24 *int    `json:"tmfs_operations_fy24"`
+}
+
+// FromAircraft maps a stored aircraft to its v2 representation in the
+// unit system
+func FromAircraft(row db.AircraftDatum, s units.System) Aircraft {
+	a := units.Convert(row, s)
+
+	return Aircraft{
+		ID:            a.ID,
+		IcaoCode:      text(a.IcaoCode),
+		FaaDesignator: text(a.FaaDesignator),
+		Manufacturer:  text(a.Manufacturer),
+		Model:         text(a.ModelFaa),
+		ModelBada:     text(a.ModelBada),
+		Classification: Classification{
+			AircraftClass: text(a.Class),
+			PhysicalClass: text(a.PhysicalClassEngine),
+			NumEngines:    integer(a.NumEngines),
+			AAC:           text(a.Aac),
+			AACMinimum:    text(a.AacMinimum),
+			AACMaximum:    text(a.AacMaximum),
+			ADG:           text(a.Adg),
+			TDG:           text(a.Tdg),
+		},
+		ApproachSpeed: ApproachSpeed{
+			Unit:    units.Speed.Unit(s),
+			Typical: integer(a.ApproachSpeedKnot),
+			Minimum: integer(a.ApproachSpeedMinimumKnot),
+			Maximum: integer(a.ApproachSpeedMaximumKnot),
+		},
+		Dimensions: Dimensions{
+			Unit:                    units.Length.Unit(s),
+			AreaUnit:                units.Area.Unit(s),
+			WingspanWithoutWinglets: units.Decimal(a.WingspanFtWithoutWingletsSharklets),
+			WingspanWithWinglets:    units.Decimal(a.WingspanFtWithWingletsSharklets),
+			Length:                  units.Decimal(a.LengthFt),
+			TailHeightAtOew:         units.Decimal(a.TailHeightAtOewFt),
+			Wheelbase:               units.Decimal(a.WheelbaseFt),
+			CockpitToMainGear:       units.Decimal(a.CockpitToMainGearFt),
+			MainGearWidth:           units.Decimal(a.MainGearWidthFt),
+			MainGearConfig:          text(a.MainGearConfig),
+			RotorDiameter:           units.Decimal(a.RotorDiameterFt),
+			ParkingArea:             units.Decimal(a.ParkingAreaFt2),
+		},
+		Weights: Weights{
+			Unit: units.Mass.Unit(s),
+			MTOW: integer(a.MtowLb),
+			MALW: integer(a.MalwLb),
+		},
+		Wake: Wake{
+			IcaoWtc:         text(a.IcaoWtc),
+			FaaWeightClass:  text(a.FaaWeight),
+			CWT:             text(a.Cwt),
+			Recat15:         text(a.OneHalfWakeCategory),
+			Recat2AppendixA: text(a.TwoWakeCategoryAppxA),
+			Recat2AppendixB: text(a.TwoWakeCategoryAppxB),
+		},
+		Operations: Operations{
+			SRS:                text(a.Srs),
+			LahsoGroup:         textInteger(a.Lahso),
+			FaaRegistered:      yesNo(a.FaaRegistry),
+			RegistrationCount:  integer(a.RegistrationCount),
+			TmfsOperationsFY24: integer(a.TmfsOperationsFy24),
+		},
+		Remarks:    text(a.Remarks),
+		LastUpdate: date(a.LastUpdate),
+	}
+}
+
+// FromAircraftList maps a list of stored aircraft
+func FromAircraftList(rows []db.AircraftDatum, s units.System) []Aircraft {
+	out := make([]Aircraft, len(rows))
+	for i, a := range rows {
+		out[i] = FromAircraft(a, s)
+	}
+	return out
+}
+
+// text returns a trimmed string, or nil when it is missing, empty or N/A
+func text(v pgtype.Text) *string {
+	s := strings.TrimSpace(v.String)
+	if !v.Valid || s == "" || strings.EqualFold(s, "N/A") {
+		return nil
+	}
+	return &s
+}
+
+func integer(v pgtype.Int4) *int {
+	if !v.Valid {
+		return nil
+	}
+	i := int(v.Int32)
+	return &i
+}
+
+// textInteger reads a number stored as text, such as the LAHSO group
+func textInteger(v pgtype.Text) *int {
+	s := text(v)
+	if s == nil {
+		return nil
+	}
+	i, err := strconv.Atoi(*s)
+	if err != nil {
+		return nil
+	}
+	return &i
+}
+
+// yesNo reads a Yes or No flag
+func yesNo(v pgtype.Text) *bool {
+	s := text(v)
+	if s == nil {
+		return nil
+	}
+	var b bool
+	switch strings.ToLower(*s) {
+	case "yes", "y", "true":
+		b = true
+	case "no", "n", "false":
+		b = false
+	default:
+		return nil
+	}
+	return &b
+}
+
+// date reads the MM-DD-YY dates of the FAA workbook as an ISO 8601 date
+func date(v pgtype.Text) *string {
+	s := text(v)
+	if s == nil {
+		return nil
+	}
+	for _, layout := range []string{"01-02-06", "01/02/06", "01-02-2006", "01/02/2006", "2006-01-02"} {
+		if t, err := time.Parse(layout, *s); err == nil {
+			iso := t.Format("2006-01-02")
+			return &iso
+		}
+	}
+	return nil
+}
@@ -0,0 +1,241 @@
+// Package export writes aircraft rows as CSV or Excel files laid out like
+// the ACD_Data sheet of the FAA workbook, so an exported file can be
+// imported again with migration.MigrateFromSource.
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/migration"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5/pgtype"
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is an export file format
+type Format string
+
+const (
+	FormatCSV  Format = "csv"
+	FormatXLSX Format = "xlsx"
+)
+
+// ParseFormat validates an export format name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(name); f {
+	case FormatCSV, FormatXLSX:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported export format %q: must be csv or xlsx", name)
+	}
+}
+
+// ContentType returns the MIME type of the format
+func (f Format) ContentType() string {
+	if f == FormatXLSX {
+		return "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// Filename returns the download file name for the format
+func (f Format) Filename() string {
+	return "aircraft_data." + string(f)
+}
+
+// Writer writes aircraft rows below a header row. Close must be called to
+// finish the file.
+type Writer interface {
+	Write(a db.AircraftDatum) error
+	Close() error
+}
+
+// NewWriter returns a writer for the format and writes the header row
+func NewWriter(format Format, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatCSV:
+		return newCSVWriter(w)
+	case FormatXLSX:
+		return newXLSXWriter(w)
+	default:
+		return nil, fmt.Errorf("unsupported export format %q", format)
+	}
+}
+
+// csvWriter streams rows straight to the output
+type csvWriter struct {
+	w    *csv.Writer
+	rows int
+}
+
+func newCSVWriter(w io.Writer) (*csvWriter, error) {
+	cw := &csvWriter{w: csv.NewWriter(w)}
+	if err := cw.w.Write(migration.Headers); err != nil {
+		return nil, err
+	}
+	return cw, nil
+}
+
+func (cw *csvWriter) Write(a db.AircraftDatum) error {
+	values := Record(a)
+	record := make([]string, len(values))
+	for i, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case string:
+			record[i] = v
+		case int32:
+			record[i] = strconv.FormatInt(int64(v), 10)
+		case float64:
+			record[i] = strconv.FormatFloat(v, 'f', -1, 64)
+		}
+	}
+	if err := cw.w.Write(record); err != nil {
+		return err
+	}
+
+	// Flush regularly so large exports reach the client as they are read
+	cw.rows++
+	if cw.rows%100 == 0 {
+		cw.w.Flush()
+		return cw.w.Error()
+	}
+	return nil
+}
+
+func (cw *csvWriter) Close() error {
+	cw.w.Flush()
+	return cw.w.Error()
+}
+
+// xlsxWriter builds the workbook with excelize's stream writer and writes
+// it out on Close, since an xlsx file is a zip archive
+type xlsxWriter struct {
+	out  io.Writer
+	file *excelize.File
+	sw   *excelize.StreamWriter
+	row  int
+}
+
+func newXLSXWriter(w io.Writer) (*xlsxWriter, error) {
+	f := excelize.NewFile()
+	if err := f.SetSheetName("Sheet1", migration.SheetName); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	sw, err := f.NewStreamWriter(migration.SheetName)
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	xw := &xlsxWriter{out: w, file: f, sw: sw, row: 1}
+
+	header := make([]any, len(migration.Headers))
+	for i, h := range migration.Headers {
+		header[i] = h
+	}
+	if err := xw.writeRow(header); err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	return xw, nil
+}
+
+func (xw *xlsxWriter) Write(a db.AircraftDatum) error {
+	return xw.writeRow(Record(a))
+}
+
+func (xw *xlsxWriter) writeRow(values []any) error {
+	cell, err := excelize.CoordinatesToCellName(1, xw.row)
+	if err != nil {
+		return err
+	}
+	xw.row++
+	return xw.sw.SetRow(cell, values)
+}
+
+func (xw *xlsxWriter) Close() error {
+	defer xw.file.Close()
+
+	if err := xw.sw.Flush(); err != nil {
+		return err
+	}
+	return xw.file.Write(xw.out)
+}
+
+// Record returns the values of an aircraft in the column order of
+// migration.Headers. Missing values are nil, text is a string, integers
+// are int32 and decimals are float64.
+func Record(a db.AircraftDatum) []any {
+	return []any{
+		text(a.IcaoCode),
+		text(a.FaaDesignator),
+		text(a.Manufacturer),
+		text(a.ModelFaa),
+		text(a.ModelBada),
+		text(a.PhysicalClassEngine),
+		integer(a.NumEngines),
+		text(a.Aac),
+		text(a.AacMinimum),
+		text(a.AacMaximum),
+		text(a.Adg),
+		text(a.Tdg),
+		integer(a.ApproachSpeedKnot),
+		integer(a.ApproachSpeedMinimumKnot),
+		integer(a.ApproachSpeedMaximumKnot),
+		decimal(a.WingspanFtWithoutWingletsSharklets),
+		decimal(a.WingspanFtWithWingletsSharklets),
+		decimal(a.LengthFt),
+		decimal(a.TailHeightAtOewFt),
+		decimal(a.WheelbaseFt),
+		decimal(a.CockpitToMainGearFt),
+		decimal(a.MainGearWidthFt),
+		integer(a.MtowLb),
+		integer(a.MalwLb),
+		text(a.MainGearConfig),
+		text(a.IcaoWtc),
+		decimal(a.ParkingAreaFt2),
+		text(a.Class),
+		text(a.FaaWeight),
+		text(a.Cwt),
+		text(a.OneHalfWakeCategory),
+		text(a.TwoWakeCategoryAppxA),
+		text(a.TwoWakeCategoryAppxB),
+		decimal(a.RotorDiameterFt),
+		text(a.Srs),
+		text(a.Lahso),
+		text(a.FaaRegistry),
+		integer(a.RegistrationCount),
+		integer(a.TmfsOperationsFy24),
+		text(a.Remarks),
+		text(a.LastUpdate),
+	}
+}
+
+func text(v pgtype.Text) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.String
+}
+
+func integer(v pgtype.Int4) any {
+	if !v.Valid {
+		return nil
+	}
+	return v.Int32
+}
+
+func decimal(v pgtype.Numeric) any {
+	if f := units.Decimal(v); f != nil {
+		return *f
+	}
+	return nil
+}
@@ -0,0 +1,332 @@
+// Package gatefit matches aircraft to parking positions described by a
+// clearance envelope of maximum wingspan, length and tail height. Gates,
+// clearances and results share the unit system the caller passes; fields
+// keep the names of the imperial units the aircraft are stored in.
+package gatefit
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+)
+
+// Gate is the clearance envelope of a parking position. Each limit is
+// optional, but at least one must be set.
+type Gate struct {
+	ID              string   `json:"id"`
+	MaxWingspanFt   *float64 `json:"max_wingspan_ft,omitempty"`
+	MaxLengthFt     *float64 `json:"max_length_ft,omitempty"`
+	MaxTailHeightFt *float64 `json:"max_tail_height_ft,omitempty"`
+}
+
+// Validate checks gates before matching. Gates without an id are named by
+// their position in the list, starting at 1.
+func Validate(gates []Gate) error {
+	if len(gates) == 0 {
+		return errors.New("at least one gate is required")
+	}
+
+	seen := map[string]bool{}
+	for i := range gates {
+		g := &gates[i]
+		g.ID = strings.TrimSpace(g.ID)
+		if g.ID == "" {
+			g.ID = strconv.Itoa(i + 1)
+		}
+		if seen[g.ID] {
+			return fmt.Errorf("duplicate gate id %q", g.ID)
+		}
+		seen[g.ID] = true
+
+		if g.MaxWingspanFt == nil && g.MaxLengthFt == nil && g.MaxTailHeightFt == nil {
+			return fmt.Errorf("gate %s: at least one of max_wingspan_ft, max_length_ft or max_tail_height_ft is required", g.ID)
+		}
+		for name, limit := range map[string]*float64{
+			"max_wingspan_ft":    g.MaxWingspanFt,
+			"max_length_ft":      g.MaxLengthFt,
+			"max_tail_height_ft": g.MaxTailHeightFt,
+		} {
+			if limit != nil && (*limit <= 0 || math.IsInf(*limit, 0) || math.IsNaN(*limit)) {
+				return fmt.Errorf("gate %s: %s must be a positive number", g.ID, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+// ParseClearance parses a wingtip clearance, which may be empty
+func ParseClearance(raw string) (float64, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return 0, nil
+	}
+	clearance, err := strconv.ParseFloat(raw, 64)
+	if err != nil || clearance < 0 || math.IsInf(clearance, 0) {
+		return 0, fmt.Errorf("invalid wingtip_clearance_ft %q: must be zero or a positive number", raw)
+	}
+	return clearance, nil
+}
+
+// csvColumns maps the accepted CSV header names to gate fields
+var csvColumns = map[string]string{
+	"id":                 "id",
+	"gate":               "id",
+	"gate_id":            "id",
+	"max_wingspan_ft":    "max_wingspan_ft",
+	"max_length_ft":      "max_length_ft",
+	"max_tail_height_ft": "max_tail_height_ft",
+}
+
+// ParseCSV reads gates from CSV with a header row naming the columns id,
+// max_wingspan_ft, max_length_ft and max_tail_height_ft in any order. An
+// empty cell leaves that limit unset.
+func ParseCSV(r io.Reader) ([]Gate, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err == io.EOF {
+		return nil, errors.New("csv is empty")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	fields := make([]string, len(header))
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(strings.TrimPrefix(name, "\ufeff")))
+		field, ok := csvColumns[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown csv column %q", name)
+		}
+		fields[i] = field
+	}
+
+	var gates []Gate
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		var g Gate
+		for i, value := range record {
+			value = strings.TrimSpace(value)
+			if value == "" {
+				continue
+			}
+			if fields[i] == "id" {
+				g.ID = value
+				continue
+			}
+
+			limit, err := strconv.ParseFloat(value, 64)
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid %s %q", line, fields[i], value)
+			}
+			switch fields[i] {
+			case "max_wingspan_ft":
+				g.MaxWingspanFt = &limit
+			case "max_length_ft":
+				g.MaxLengthFt = &limit
+			case "max_tail_height_ft":
+				g.MaxTailHeightFt = &limit
+			}
+		}
+		gates = append(gates, g)
+	}
+
+	return gates, nil
+}
+
+// Dimensions are the aircraft dimensions a gate constrains
+type Dimensions struct {
+	WingspanFt   *float64 `json:"wingspan_ft"`
+	LengthFt     *float64 `json:"length_ft"`
+	TailHeightFt *float64 `json:"tail_height_ft"`
+}
+
+// FromAircraft reads the dimensions of a stored aircraft in the system. The
+// wingspan with winglets or sharklets is used when known.
+func FromAircraft(a db.AircraftDatum, s units.System) Dimensions {
+	a = units.Convert(a, s)
+	d := Dimensions{
+		WingspanFt:   units.Decimal(a.WingspanFtWithWingletsSharklets),
+		LengthFt:     units.Decimal(a.LengthFt),
+		TailHeightFt: units.Decimal(a.TailHeightAtOewFt),
+	}
+	if d.WingspanFt == nil {
+		d.WingspanFt = units.Decimal(a.WingspanFtWithoutWingletsSharklets)
+	}
+	return d
+}
+
+// Margins are the room left in a gate once an aircraft is parked.
+// WingtipClearanceFt is the distance from each wingtip to the envelope edge
+// with the aircraft centred. Margins for unconstrained dimensions are nil.
+type Margins struct {
+	WingtipClearanceFt *float64 `json:"wingtip_clearance_ft,omitempty"`
+	LengthFt           *float64 `json:"length_ft,omitempty"`
+	TailHeightFt       *float64 `json:"tail_height_ft,omitempty"`
+}
+
+// Check tests whether an aircraft fits a gate, leaving at least clearance
+// beyond each wingtip. It returns the margins and, when the aircraft does
+// not fit, the reasons why, in the units of s. An unknown dimension that
+// the gate limits does not fit, since it cannot be confirmed.
+func Check(g Gate, d Dimensions, clearance float64, s units.System) (Margins, []string) {
+	var (
+		m       Margins
+		reasons []string
+		unit    = units.Length.Unit(s)
+	)
+
+	if g.MaxWingspanFt != nil {
+		if d.WingspanFt == nil {
+			reasons = append(reasons, "wingspan is unknown")
+		} else {
+			tip := round((*g.MaxWingspanFt - *d.WingspanFt) / 2)
+			m.WingtipClearanceFt = &tip
+			if tip < clearance {
+				reasons = append(reasons, fmt.Sprintf("wingspan of %s %s leaves %s %s at each wingtip, less than the %s %s clearance",
+					format(*d.WingspanFt), unit, format(tip), unit, format(clearance), unit))
+			}
+		}
+	}
+
+	for _, limit := range []struct {
+		label  string
+		max    *float64
+		value  *float64
+		margin **float64
+	}{
+		{"length", g.MaxLengthFt, d.LengthFt, &m.LengthFt},
+		{"tail height", g.MaxTailHeightFt, d.TailHeightFt, &m.TailHeightFt},
+	} {
+		if limit.max == nil {
+			continue
+		}
+		if limit.value == nil {
+			reasons = append(reasons, limit.label+" is unknown")
+			continue
+		}
+		margin := round(*limit.max - *limit.value)
+		*limit.margin = &margin
+		if margin < 0 {
+			reasons = append(reasons, fmt.Sprintf("%s of %s %s exceeds the %s %s limit",
+				limit.label, format(*limit.value), unit, format(*limit.max), unit))
+		}
+	}
+
+	return m, reasons
+}
+
+// Aircraft is an aircraft that fits a gate
+type Aircraft struct {
+	ID            int32      `json:"id"`
+	IcaoCode      string     `json:"icao_code"`
+	FaaDesignator string     `json:"faa_designator"`
+	Manufacturer  string     `json:"manufacturer"`
+	ModelFaa      string     `json:"model_faa"`
+	Dimensions    Dimensions `json:"dimensions"`
+	Margins       Margins    `json:"margins"`
+}
+
+// GateResult lists the aircraft that fit a gate
+type GateResult struct {
+	Gate     Gate       `json:"gate"`
+	Count    int        `json:"count"`
+	Aircraft []Aircraft `json:"aircraft"`
+}
+
+// Match checks every aircraft in the database against each gate, listing
+// the fitting aircraft in the default manufacturer and model order
+func Match(ctx context.Context, conn db.DBTX, gates []Gate, clearance float64, s units.System) ([]GateResult, error) {
+	results := make([]GateResult, len(gates))
+	for i, g := range gates {
+		results[i] = GateResult{Gate: g, Aircraft: []Aircraft{}}
+	}
+
+	err := search.Each(ctx, conn, search.Query{}, func(a db.AircraftDatum) error {
+		d := FromAircraft(a, s)
+		for i, g := range gates {
+			margins, reasons := Check(g, d, clearance, s)
+			if len(reasons) > 0 {
+				continue
+			}
+			results[i].Aircraft = append(results[i].Aircraft, Aircraft{
+				ID:            a.ID,
+				IcaoCode:      a.IcaoCode.String,
+				FaaDesignator: a.FaaDesignator.String,
+				Manufacturer:  a.Manufacturer.String,
+				ModelFaa:      a.ModelFaa.String,
+				Dimensions:    d,
+				Margins:       margins,
+			})
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range results {
+		results[i].Count = len(results[i].Aircraft)
+	}
+	return results, nil
+}
+
+// GateFit is a gate checked against a single aircraft
+type GateFit struct {
+	Gate    Gate     `json:"gate"`
+	Margins Margins  `json:"margins"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Report lists the gates an aircraft fits and those it does not
+type Report struct {
+	Dimensions Dimensions `json:"dimensions"`
+	Fits       []GateFit  `json:"fits"`
+	DoesNotFit []GateFit  `json:"does_not_fit"`
+}
+
+// ForAircraft checks a single aircraft against each gate
+func ForAircraft(a db.AircraftDatum, gates []Gate, clearance float64, s units.System) Report {
+	d := FromAircraft(a, s)
+	report := Report{Dimensions: d, Fits: []GateFit{}, DoesNotFit: []GateFit{}}
+
+	for _, g := range gates {
+		margins, reasons := Check(g, d, clearance, s)
+		fit := GateFit{Gate: g, Margins: margins, Reasons: reasons}
+		if len(reasons) == 0 {
+			report.Fits = append(report.Fits, fit)
+		} else {
+			report.DoesNotFit = append(report.DoesNotFit, fit)
+		}
+	}
+
+	return report
+}
+
+// round rounds a distance to hundredths of its unit
+func round(v float64) float64 {
+	return math.Round(v*100) / 100
+}
+
+// format renders a number without trailing zeros
+func format(v float64) string {
+	return strconv.FormatFloat(round(v), 'f', -1, 64)
+}
@@ -0,0 +1,172 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/labstack/echo/v4"
+)
+
+// BatchRequest represents the body of a batch lookup.
+// Codes are matched case-insensitively; any mix of the lists may be given.
+type BatchRequest struct {
+	ICAOCodes      []string `json:"icao_codes"`
+	FAADesignators []string `json:"faa_designators"`
+	IDs            []int32  `json:"ids"`
+}
+
+// BatchResponse maps every resolved input to its matching aircraft and
+// lists the inputs that matched nothing
+type BatchResponse struct {
+	ICAOCodes      map[string][]units.Aircraft `json:"icao_codes"`
+	FAADesignators map[string][]units.Aircraft `json:"faa_designators"`
+	IDs            map[int32]units.Aircraft    `json:"ids"`
+	Unresolved     BatchRequest                `json:"unresolved"`
+}
+
+// BatchLookup handles POST /api/aircraft/batch
+func (h *Handlers) BatchLookup(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
+	defer cancel()
+
+	req := &BatchRequest{}
+	if err := c.Bind(req); err != nil {
+		middleware.RecordDatabaseQuery("get_batch", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid batch request body",
+		})
+	}
+
+	// Normalize codes, keeping the first spelling of each input as its key
+	icaoInputs := normalizeCodes(req.ICAOCodes)
+	faaInputs := normalizeCodes(req.FAADesignators)
+	ids := uniqueIDs(req.IDs)
+
+	size := len(icaoInputs) + len(faaInputs) + len(ids)
+	if size == 0 {
+		middleware.RecordDatabaseQuery("get_batch", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "At least one ICAO code, FAA designator or id is required",
+		})
+	}
+	if size > h.config.MaxBatchSize {
+		middleware.RecordDatabaseQuery("get_batch", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "batch_too_large",
+			Message: fmt.Sprintf("Batch contains %d inputs, the maximum is %d", size, h.config.MaxBatchSize),
+		})
+	}
+
+	aircraft, err := h.db.Queries.GetAircraftBatch(ctx, db.GetAircraftBatchParams{
+		IcaoCodes:      keys(icaoInputs),
+		FaaDesignators: keys(faaInputs),
+		Ids:            ids,
+	})
+	middleware.RecordDatabaseQuery("get_batch", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	response := BatchResponse{
+		ICAOCodes:      map[string][]units.Aircraft{},
+		FAADesignators: map[string][]units.Aircraft{},
+		IDs:            map[int32]units.Aircraft{},
+		Unresolved: BatchRequest{
+			ICAOCodes:      []string{},
+			FAADesignators: []string{},
+			IDs:            []int32{},
+		},
+	}
+
+	// A row can answer several inputs, e.g. both its ICAO code and its id
+	system := units.FromContext(ctx)
+	for _, row := range aircraft {
+		a := units.NewAircraft(row, system)
+		if input, ok := icaoInputs[strings.ToUpper(a.IcaoCode.String)]; ok && a.IcaoCode.Valid {
+			response.ICAOCodes[input] = append(response.ICAOCodes[input], a)
+		}
+		if input, ok := faaInputs[strings.ToUpper(a.FaaDesignator.String)]; ok && a.FaaDesignator.Valid {
+			response.FAADesignators[input] = append(response.FAADesignators[input], a)
+		}
+		response.IDs[a.ID] = a
+	}
+
+	for _, input := range icaoInputs {
+		if _, ok := response.ICAOCodes[input]; !ok {
+			response.Unresolved.ICAOCodes = append(response.Unresolved.ICAOCodes, input)
+		}
+	}
+	for _, input := range faaInputs {
+		if _, ok := response.FAADesignators[input]; !ok {
+			response.Unresolved.FAADesignators = append(response.Unresolved.FAADesignators, input)
+		}
+	}
+
+	// Only keep id entries that were asked for
+	requested := make(map[int32]bool, len(ids))
+	for _, id := range ids {
+		requested[id] = true
+		if _, ok := response.IDs[id]; !ok {
+			response.Unresolved.IDs = append(response.Unresolved.IDs, id)
+		}
+	}
+	for id := range response.IDs {
+		if !requested[id] {
+			delete(response.IDs, id)
+		}
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// normalizeCodes maps each upper-cased, trimmed code to the input spelling
+// it was first given as, skipping blanks
+func normalizeCodes(codes []string) map[string]string {
+	normalized := make(map[string]string, len(codes))
+	for _, code := range codes {
+		code = strings.TrimSpace(code)
+		if code == "" {
+			continue
+		}
+		key := strings.ToUpper(code)
+		if _, ok := normalized[key]; !ok {
+			normalized[key] = code
+		}
+	}
+	return normalized
+}
+
+// keys returns the keys of a normalized code map
+func keys(m map[string]string) []string {
+	result := make([]string, 0, len(m))
+	for k := range m {
+		result = append(result, k)
+	}
+	return result
+}
+
+// uniqueIDs returns ids with duplicates removed, preserving order
+func uniqueIDs(ids []int32) []int32 {
+	seen := make(map[int32]bool, len(ids))
+	result := make([]int32, 0, len(ids))
+	for _, id := range ids {
+		if !seen[id] {
+			seen[id] = true
+			result = append(result, id)
+		}
+	}
+	return result
+}
@@ -0,0 +1,102 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/classify"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/labstack/echo/v4"
+)
+
+// ClassificationMismatch lists the stored groups of an aircraft that
+// disagree with the groups computed from its dimensions. Error is set
+// instead when its dimensions cannot be classified.
+type ClassificationMismatch struct {
+	ID            int32               `json:"id"`
+	IcaoCode      string              `json:"icao_code"`
+	FaaDesignator string              `json:"faa_designator"`
+	ModelFaa      string              `json:"model_faa"`
+	Mismatches    []classify.Mismatch `json:"mismatches,omitempty"`
+	Error         string              `json:"error,omitempty"`
+}
+
+// MismatchResponse represents the classification mismatch report
+type MismatchResponse struct {
+	Checked  int                      `json:"checked"`
+	Aircraft []ClassificationMismatch `json:"aircraft"`
+}
+
+// Classify handles POST /api/classify
+func (h *Handlers) Classify(c echo.Context) error {
+	var in classify.Input
+	if err := c.Bind(&in); err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Invalid classification request body",
+		})
+	}
+
+	if in.IsEmpty() {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "At least one aircraft dimension is required",
+		})
+	}
+
+	// Dimensions may be sent in metric units with units=metric
+	result, err := classify.Classify(in.ToImperial(units.FromContext(c.Request().Context())))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_input",
+			Message: err.Error(),
+		})
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
+
+// ClassificationMismatches handles GET /api/classify/mismatches
+func (h *Handlers) ClassificationMismatches(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+
+	response := MismatchResponse{Aircraft: []ClassificationMismatch{}}
+
+	err := search.Each(ctx, h.db.Pool, search.Query{}, func(a db.AircraftDatum) error {
+		response.Checked++
+
+		mismatches, err := classify.Check(a)
+		if err == nil && len(mismatches) == 0 {
+			return nil
+		}
+
+		item := ClassificationMismatch{
+			ID:            a.ID,
+			IcaoCode:      a.IcaoCode.String,
+			FaaDesignator: a.FaaDesignator.String,
+			ModelFaa:      a.ModelFaa.String,
+			Mismatches:    mismatches,
+		}
+		if err != nil {
+			item.Error = err.Error()
+		}
+		response.Aircraft = append(response.Aircraft, item)
+		return nil
+	})
+	middleware.RecordDatabaseQuery("classify_mismatches", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/compare"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/dukerupert/faa-aircraft-search/web/templates/pages"
+	"github.com/labstack/echo/v4"
+)
+
+// CompareAircraft handles GET /api/aircraft/compare?ids=1,2,3
+func (h *Handlers) CompareAircraft(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	ids, err := compare.ParseIDs(c.QueryParams()["ids"])
+	if err != nil {
+		middleware.RecordDatabaseQuery("compare", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_ids",
+			Message: err.Error(),
+		})
+	}
+
+	aircraft, missing, err := h.aircraftByIDs(ctx, ids)
+	middleware.RecordDatabaseQuery("compare", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	if len(missing) > 0 {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: "No aircraft found for ids " + formatIDs(missing),
+		})
+	}
+
+	return c.JSON(http.StatusOK, compare.New(aircraft, units.FromContext(ctx)))
+}
+
+// Compare renders the side-by-side comparison page
+func (h *Handlers) Compare(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	ids, err := compare.ParseIDs(c.QueryParams()["ids"])
+	if err != nil {
+		middleware.RecordDatabaseQuery("compare", time.Since(start), false)
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	aircraft, missing, err := h.aircraftByIDs(ctx, ids)
+	middleware.RecordDatabaseQuery("compare", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Database error")
+	}
+	if len(missing) > 0 {
+		return c.String(http.StatusNotFound, "Aircraft not found: "+formatIDs(missing))
+	}
+
+	return pages.Compare(compare.New(aircraft, units.FromContext(ctx))).Render(ctx, c.Response().Writer)
+}
+
+// aircraftByIDs fetches aircraft in the order of ids and returns the ids
+// that do not exist
+func (h *Handlers) aircraftByIDs(ctx context.Context, ids []int32) ([]db.AircraftDatum, []int32, error) {
+	rows, err := h.db.Queries.GetAircraftBatch(ctx, db.GetAircraftBatchParams{
+		IcaoCodes:      []string{},
+		FaaDesignators: []string{},
+		Ids:            ids,
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byID := make(map[int32]db.AircraftDatum, len(rows))
+	for _, a := range rows {
+		byID[a.ID] = a
+	}
+
+	var aircraft []db.AircraftDatum
+	var missing []int32
+	for _, id := range ids {
+		if a, ok := byID[id]; ok {
+			aircraft = append(aircraft, a)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+
+	return aircraft, missing, nil
+}
+
+// formatIDs joins ids for error messages
+func formatIDs(ids []int32) string {
+	parts := make([]string, len(ids))
+	for i, id := range ids {
+		parts[i] = fmt.Sprint(id)
+	}
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/compat"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/dukerupert/faa-aircraft-search/web/templates/pages"
+	"github.com/labstack/echo/v4"
+)
+
+// Compatibility handles GET /api/compatibility
+func (h *Handlers) Compatibility(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	system := units.FromContext(ctx)
+
+	criteria, err := compat.ParseCriteria(c.QueryParams())
+	if err != nil {
+		middleware.RecordDatabaseQuery("compatibility", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_criteria",
+			Message: err.Error(),
+		})
+	}
+
+	report, err := compat.Evaluate(ctx, h.db.Pool, criteria, system)
+	middleware.RecordDatabaseQuery("compatibility", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	return jsonInUnits(c, report, system)
+}
+
+// CompatibilityPage renders the runway and taxiway compatibility checker.
+// The form is shown on its own until design parameters are submitted.
+func (h *Handlers) CompatibilityPage(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+
+	params := c.QueryParams()
+	if len(params) == 0 {
+		return pages.Compatibility(params, nil, "").Render(ctx, c.Response().Writer)
+	}
+
+	criteria, err := compat.ParseCriteria(params)
+	if err != nil {
+		return pages.Compatibility(params, nil, err.Error()).Render(ctx, c.Response().Writer)
+	}
+
+	report, err := compat.Evaluate(ctx, h.db.Pool, criteria, units.FromContext(ctx))
+	middleware.RecordDatabaseQuery("compatibility", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Database error")
+	}
+
+	return pages.Compatibility(params, &report, "").Render(ctx, c.Response().Writer)
+}
@@ -0,0 +1,103 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/diff"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/migration"
+	"github.com/labstack/echo/v4"
+)
+
+// MaxDiffUpload is the largest file accepted by the diff endpoint, in any
+// of the import formats
+const MaxDiffUpload = 20 << 20
+
+// DiffRelease handles POST /api/diff. It compares an uploaded release in
+// any import format with the current data without importing it. The format
+// is taken from the file name, or guessed from the content, as in the CLI.
+func (h *Handlers) DiffRelease(c echo.Context) error {
+	format := diff.FormatJSON
+	if name := c.QueryParam("format"); name != "" {
+		f, err := diff.ParseFormat(name)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_format",
+				Message: err.Error(),
+			})
+		}
+		format = f
+	}
+
+	c.Request().Body = http.MaxBytesReader(c.Response(), c.Request().Body, MaxDiffUpload)
+	header, err := c.FormFile("file")
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			return c.JSON(http.StatusRequestEntityTooLarge, ErrorResponse{
+				Error:   "file_too_large",
+				Message: fmt.Sprintf("The file must not exceed %d MB", MaxDiffUpload>>20),
+			})
+		}
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "missing_file",
+			Message: "Upload the release as the file field of a multipart form",
+		})
+	}
+
+	file, err := header.Open()
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_file",
+			Message: "Failed to read the uploaded file",
+		})
+	}
+	defer file.Close()
+
+	src, err := migration.ReadSource(file, header.Filename, "")
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_file",
+			Message: "Failed to read the uploaded file",
+		})
+	}
+	incoming, err := migration.ReadAll(src, migration.Options{})
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_file",
+			Message: fmt.Sprintf("Not an FAA release in the %s format: %v", src.Format, err),
+		})
+	}
+
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 20*time.Second)
+	defer cancel()
+
+	report, err := diff.CompareCurrent(ctx, h.db.Pool, incoming)
+	middleware.RecordDatabaseQuery("diff", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	if format == diff.FormatJSON {
+		return c.JSON(http.StatusOK, report)
+	}
+
+	var buf bytes.Buffer
+	if err := diff.Write(&buf, report, format); err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "diff_error",
+			Message: "Failed to render the report",
+		})
+	}
+	return c.Blob(http.StatusOK, format.ContentType(), buf.Bytes())
+}
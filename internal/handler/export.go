@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/export"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/labstack/echo/v4"
+)
+
+// IsExport reports whether a request downloads a search export. Exports
+// stream for longer than the request timeout of the server allows and
+// bound themselves, so the timeout middleware skips them.
+func IsExport(c echo.Context) bool {
+	switch c.Path() {
+	case "/api/v1/aircraft/search", "/api/v2/aircraft/search":
+		return c.QueryParam("format") != ""
+	default:
+		return false
+	}
+}
+
+// exportAircraft streams every aircraft matching the query as a file.
+// Paging is ignored so the export covers the whole result set.
+func (h *Handlers) exportAircraft(c echo.Context, format export.Format, query search.Query) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 2*time.Minute)
+	defer cancel()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, `attachment; filename="`+format.Filename()+`"`)
+
+	w, err := export.NewWriter(format, res)
+	if err != nil {
+		middleware.RecordDatabaseQuery("export", time.Since(start), false)
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "export_error",
+			Message: "Failed to start export",
+		})
+	}
+
+	err = search.Each(ctx, h.db.Pool, query, func(a db.AircraftDatum) error {
+		return w.Write(a)
+	})
+	if err == nil {
+		err = w.Close()
+	}
+	middleware.RecordDatabaseQuery("export", time.Since(start), err == nil)
+
+	if err != nil {
+		// Once rows have been streamed the status is already sent, so
+		// the error can only be logged and the download cut short
+		if !res.Committed {
+			res.Header().Del(echo.HeaderContentDisposition)
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Error:   "database_error",
+				Message: "Failed to export aircraft data",
+			})
+		}
+		c.Logger().Errorf("export failed: %v", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,202 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/gatefit"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// GateRequest represents a JSON body of gate envelopes. Gates may instead
+// be uploaded as CSV, with the clearance as a query parameter. Distances
+// are read in the unit system of the request.
+type GateRequest struct {
+	Gates              []gatefit.Gate `json:"gates"`
+	WingtipClearanceFt *float64       `json:"wingtip_clearance_ft"`
+}
+
+// GateFitResponse lists the fitting aircraft of each gate. Unit is the
+// unit of every distance; in metric the fields are renamed with units.Name.
+type GateFitResponse struct {
+	Unit               string               `json:"unit"`
+	WingtipClearanceFt float64              `json:"wingtip_clearance_ft"`
+	Gates              []gatefit.GateResult `json:"gates"`
+}
+
+// AircraftGatesResponse lists the gates an aircraft fits, with distances
+// named and labelled like GateFitResponse
+type AircraftGatesResponse struct {
+	Aircraft           units.Aircraft `json:"aircraft"`
+	Unit               string         `json:"unit"`
+	WingtipClearanceFt float64        `json:"wingtip_clearance_ft"`
+	gatefit.Report
+}
+
+// GateFit handles POST /api/gates/fit
+func (h *Handlers) GateFit(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 30*time.Second)
+	defer cancel()
+	system := units.FromContext(ctx)
+
+	gates, clearance, invalid := h.bindGates(c)
+	if invalid != nil {
+		middleware.RecordDatabaseQuery("gate_fit", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+
+	results, err := gatefit.Match(ctx, h.db.Pool, gates, clearance, system)
+	middleware.RecordDatabaseQuery("gate_fit", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	return jsonInUnits(c, GateFitResponse{
+		Unit:               units.Length.Unit(system),
+		WingtipClearanceFt: clearance,
+		Gates:              results,
+	}, system)
+}
+
+// AircraftGates handles POST /api/aircraft/:id/gates
+func (h *Handlers) AircraftGates(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+	system := units.FromContext(ctx)
+
+	id, err := strconv.ParseInt(c.Param("id"), 10, 32)
+	if err != nil {
+		middleware.RecordDatabaseQuery("aircraft_gates", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_id",
+			Message: "Invalid aircraft ID",
+		})
+	}
+
+	gates, clearance, invalid := h.bindGates(c)
+	if invalid != nil {
+		middleware.RecordDatabaseQuery("aircraft_gates", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, invalid)
+	}
+
+	aircraft, err := h.db.Queries.GetAircraft(ctx, int32(id))
+	middleware.RecordDatabaseQuery("aircraft_gates", time.Since(start), err == nil)
+
+	if err != nil {
+		if err == pgx.ErrNoRows {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Error:   "not_found",
+				Message: "Aircraft not found",
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	return jsonInUnits(c, AircraftGatesResponse{
+		Aircraft:           units.NewAircraft(aircraft, system),
+		Unit:               units.Length.Unit(system),
+		WingtipClearanceFt: clearance,
+		Report:             gatefit.ForAircraft(aircraft, gates, clearance, system),
+	}, system)
+}
+
+// bindGates reads gate envelopes from a JSON body, a text/csv body or a
+// multipart upload in the "file" field. A wingtip_clearance_ft query or
+// form parameter overrides the JSON field. Like the classify input, the
+// fields keep their names and take values in the request's unit system.
+func (h *Handlers) bindGates(c echo.Context) ([]gatefit.Gate, float64, *ErrorResponse) {
+	contentType := c.Request().Header.Get(echo.HeaderContentType)
+
+	var (
+		req GateRequest
+		err error
+	)
+	switch {
+	case strings.HasPrefix(contentType, echo.MIMEMultipartForm):
+		file, ferr := c.FormFile("file")
+		if ferr != nil {
+			return nil, 0, invalidGates("A CSV file is required in the file field")
+		}
+		src, ferr := file.Open()
+		if ferr != nil {
+			return nil, 0, invalidGates("Failed to read the uploaded file")
+		}
+		defer src.Close()
+		req.Gates, err = gatefit.ParseCSV(src)
+	case strings.HasPrefix(contentType, "text/csv"):
+		req.Gates, err = gatefit.ParseCSV(c.Request().Body)
+	default:
+		if berr := c.Bind(&req); berr != nil {
+			return nil, 0, &ErrorResponse{
+				Error:   "invalid_request",
+				Message: "Invalid gate request body",
+			}
+		}
+	}
+	if err != nil {
+		return nil, 0, invalidGates("Invalid gate CSV: " + err.Error())
+	}
+
+	var clearance float64
+	if req.WingtipClearanceFt != nil {
+		clearance = *req.WingtipClearanceFt
+		if clearance < 0 {
+			return nil, 0, invalidGates("wingtip_clearance_ft must not be negative")
+		}
+	}
+	if raw := c.FormValue("wingtip_clearance_ft"); raw != "" {
+		if clearance, err = gatefit.ParseClearance(raw); err != nil {
+			return nil, 0, invalidGates(err.Error())
+		}
+	}
+
+	if len(req.Gates) > h.config.MaxBatchSize {
+		return nil, 0, &ErrorResponse{
+			Error:   "batch_too_large",
+			Message: fmt.Sprintf("Request contains %d gates, the maximum is %d", len(req.Gates), h.config.MaxBatchSize),
+		}
+	}
+	if err := gatefit.Validate(req.Gates); err != nil {
+		return nil, 0, invalidGates(err.Error())
+	}
+
+	return req.Gates, clearance, nil
+}
+
+// jsonInUnits writes a 200 response whose distances are already in the
+// system, renaming the fields named after imperial units to match
+func jsonInUnits(c echo.Context, v any, s units.System) error {
+	data, err := json.Marshal(v)
+	if err == nil {
+		data, err = units.Rename(data, s)
+	}
+	if err != nil {
+		return err
+	}
+	return c.JSONBlob(http.StatusOK, data)
+}
+
+// invalidGates describes unusable gate input
+func invalidGates(message string) *ErrorResponse {
+	return &ErrorResponse{
+		Error:   "invalid_gates",
+		Message: message,
+	}
+}
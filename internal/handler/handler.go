@@ -2,35 +2,83 @@ package handler
 
 import (
 	"context"
+	"errors"
 	"net/http"
+	"os"
 	"strconv"
 	"strings"
 	"time"
 
 	"github.com/dukerupert/faa-aircraft-search/internal/database"
 	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/export"
 	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
 	"github.com/jackc/pgx/v5"
 	"github.com/labstack/echo/v4"
 )
 
 type Handlers struct {
-	db *database.Database
+	db     *database.Database
+	config Config
 }
 
-// SearchRequest represents the search query parameters
+// Config holds tunable limits for the handlers
+type Config struct {
+	// MaxBatchSize caps the number of inputs accepted by the batch lookup
+	MaxBatchSize int
+}
+
+// DefaultConfig returns the configuration used by New
+func DefaultConfig() Config {
+	return Config{
+		MaxBatchSize: 500,
+	}
+}
+
+// ConfigFromEnv reads handler configuration from environment variables,
+// falling back to DefaultConfig for unset or invalid values
+func ConfigFromEnv() Config {
+	config := DefaultConfig()
+
+	if v, err := strconv.Atoi(os.Getenv("BATCH_MAX_SIZE")); err == nil && v > 0 {
+		config.MaxBatchSize = v
+	}
+
+	return config
+}
+
+// SearchRequest represents the search query parameters.
+// Attribute filters are read separately with search.ParseFilter.
+// When Cursor is set it takes precedence over Page. When Format is set
+// every matching row is exported as a file instead of returning a page.
 type SearchRequest struct {
-	Query string `query:"q"`
-	Page  int    `query:"page"`
-	Limit int    `query:"limit"`
+	Query        string `query:"q"`
+	Sort         string `query:"sort"`
+	Page         int    `query:"page"`
+	Limit        int    `query:"limit"`
+	Cursor       string `query:"cursor"`
+	IncludeTotal bool   `query:"include_total"`
+	Format       string `query:"format"`
+	Facets       string `query:"facets"`
 }
 
-// SearchResponse represents the search API response
+// SearchResponse represents the search API response.
+// Total is omitted when the request sets include_total=false, and Facets
+// is only present when facets were requested. Aircraft holds the list in
+// the representation of the API version.
 type SearchResponse struct {
-	Aircraft []db.AircraftDatum `json:"aircraft"`
-	Total    int64              `json:"total"`
-	Page     int                `json:"page"`
-	Limit    int                `json:"limit"`
+	Aircraft   any                     `json:"aircraft"`
+	Total      *int64                  `json:"total,omitempty"`
+	Page       int                     `json:"page"`
+	Limit      int                     `json:"limit"`
+	NextCursor string                  `json:"next_cursor,omitempty"`
+	PrevCursor string                  `json:"prev_cursor,omitempty"`
+	Filters    map[string][]string     `json:"filters,omitempty"`
+	Ranges     map[string]search.Range `json:"ranges,omitempty"`
+	Sort       string                  `json:"sort,omitempty"`
+	Facets     map[string]search.Facet `json:"facets,omitempty"`
 }
 
 // ErrorResponse represents an error response
@@ -39,6 +87,15 @@ type ErrorResponse struct {
 	Message string `json:"message,omitempty"`
 }
 
+// InvalidUnits answers an API request whose units parameter is invalid.
+// It is passed to middleware.Units.
+func InvalidUnits(c echo.Context, err error) error {
+	return c.JSON(http.StatusBadRequest, ErrorResponse{
+		Error:   "invalid_units",
+		Message: err.Error(),
+	})
+}
+
 // HealthResponse represents the health check response
 type HealthResponse struct {
 	Status        string    `json:"status"`
@@ -48,19 +105,30 @@ type HealthResponse struct {
 }
 
 func New(db *database.Database) *Handlers {
-	return &Handlers{db: db}
+	return NewWithConfig(db, DefaultConfig())
+}
+
+func NewWithConfig(db *database.Database, config Config) *Handlers {
+	return &Handlers{db: db, config: config}
 }
 
 // SearchAircraft handles GET /api/aircraft/search
 func (h *Handlers) SearchAircraft(c echo.Context) error {
+	return h.searchAircraft(c, v1Representation)
+}
+
+// searchAircraft runs a search and renders the page of aircraft in the
+// representation of an API version
+func (h *Handlers) searchAircraft(c echo.Context, rep representation) error {
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 10*time.Second)
 	defer cancel()
 
 	// Bind query parameters
 	req := &SearchRequest{
-		Page:  1,
-		Limit: 50,
+		Page:         1,
+		Limit:        50,
+		IncludeTotal: true,
 	}
 
 	if err := c.Bind(req); err != nil {
@@ -79,76 +147,136 @@ func (h *Handlers) SearchAircraft(c echo.Context) error {
 		req.Limit = 50
 	}
 
-	offset := int32((req.Page - 1) * req.Limit)
-	limit := int32(req.Limit)
+	filter, err := search.ParseFilter(c.QueryParams())
+	if err != nil {
+		middleware.RecordDatabaseQuery("search", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_filter",
+			Message: err.Error(),
+		})
+	}
 
-	var aircraft []db.AircraftDatum
-	var total int64
-	var err error
-	var queryType string
+	sort, err := search.ParseSort(req.Sort)
+	if err != nil {
+		middleware.RecordDatabaseQuery("search", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_sort",
+			Message: err.Error(),
+		})
+	}
 
-	if req.Query == "" {
-		// Get all aircraft with pagination
-		queryType = "browse"
-		
-		queryStart := time.Now()
-		aircraft, err = h.db.Queries.GetAllAircraft(ctx, db.GetAllAircraftParams{
-			Limit:  limit,
-			Offset: offset,
+	facets, err := search.ParseFacets(req.Facets)
+	if err != nil {
+		middleware.RecordDatabaseQuery("search", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_facets",
+			Message: err.Error(),
 		})
-		middleware.RecordDatabaseQuery("get_all", time.Since(queryStart), err == nil)
-		
+	}
+
+	version, status, errResp := h.datasetVersion(ctx, c)
+	if errResp != nil {
+		middleware.RecordDatabaseQuery("search", time.Since(start), false)
+		return c.JSON(status, errResp)
+	}
+
+	query := search.Query{
+		Text:    strings.TrimSpace(req.Query),
+		Filter:  filter,
+		Sort:    sort,
+		Limit:   int32(req.Limit),
+		Offset:  int32((req.Page - 1) * req.Limit),
+		Version: version,
+		Units:   units.FromContext(ctx),
+	}
+
+	if req.Format != "" {
+		format, err := export.ParseFormat(req.Format)
 		if err != nil {
-			middleware.RecordAircraftSearch(queryType, time.Since(start))
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "database_error",
-				Message: "Failed to retrieve aircraft data",
+			middleware.RecordDatabaseQuery("export", time.Since(start), false)
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_format",
+				Message: err.Error(),
 			})
 		}
+		return h.exportAircraft(c, format, query)
+	}
 
-		// Get total count
-		countStart := time.Now()
-		total, err = h.db.Queries.CountAircraft(ctx)
-		middleware.RecordDatabaseQuery("count", time.Since(countStart), err == nil)
-		
+	if req.Cursor != "" {
+		query.Cursor, err = search.DecodeCursor(req.Cursor, sort)
 		if err != nil {
-			middleware.RecordAircraftSearch(queryType, time.Since(start))
-			return c.JSON(http.StatusInternalServerError, ErrorResponse{
-				Error:   "database_error",
-				Message: "Failed to count aircraft records",
+			middleware.RecordDatabaseQuery("search", time.Since(start), false)
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: err.Error(),
 			})
 		}
-	} else {
-		// Search aircraft with the query
-		queryType = "search"
-		searchTerm := "%" + strings.ToUpper(req.Query) + "%"
-		
-		searchStart := time.Now()
-		aircraft, err = h.db.Queries.SearchAircraft(ctx, db.SearchAircraftParams{
-			SearchTerm: searchTerm,
-			Limit:      limit,
-			Offset:     offset,
+	}
+
+	// Browse when neither a search term nor filters were given
+	queryType := "search"
+	listQuery, countQuery := "search", "search_count"
+	if query.Text == "" && filter.IsEmpty() {
+		queryType = "browse"
+		listQuery, countQuery = "get_all", "count"
+	}
+
+	searchStart := time.Now()
+	page, err := search.AircraftPage(ctx, h.db.Pool, query)
+	middleware.RecordDatabaseQuery(listQuery, time.Since(searchStart), err == nil)
+
+	if err != nil {
+		middleware.RecordAircraftSearch(queryType, time.Since(start))
+		if errors.Is(err, search.ErrInvalidCursor) {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Error:   "invalid_cursor",
+				Message: err.Error(),
+			})
+		}
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to search aircraft data",
 		})
-		middleware.RecordDatabaseQuery("search", time.Since(searchStart), err == nil)
-		
+	}
+
+	response := SearchResponse{
+		Aircraft:   rep.list(page.Aircraft, units.FromContext(ctx)),
+		Page:       req.Page,
+		Limit:      req.Limit,
+		NextCursor: page.NextCursor,
+		PrevCursor: page.PrevCursor,
+		Filters:    filter.Values,
+		Ranges:     filter.Ranges,
+		Sort:       sort.String(),
+	}
+
+	// Get result count with the same conditions unless the caller opted out
+	if req.IncludeTotal {
+		countStart := time.Now()
+		total, err := search.Count(ctx, h.db.Pool, query)
+		middleware.RecordDatabaseQuery(countQuery, time.Since(countStart), err == nil)
+
 		if err != nil {
 			middleware.RecordAircraftSearch(queryType, time.Since(start))
 			return c.JSON(http.StatusInternalServerError, ErrorResponse{
 				Error:   "database_error",
-				Message: "Failed to search aircraft data",
+				Message: "Failed to count search results",
 			})
 		}
+		response.Total = &total
+	}
+
+	// Count results per facet bucket when requested
+	if len(facets) > 0 {
+		facetStart := time.Now()
+		response.Facets, err = search.Facets(ctx, h.db.Pool, query, facets)
+		middleware.RecordDatabaseQuery("facets", time.Since(facetStart), err == nil)
 
-		// Get search result count
-		countStart := time.Now()
-		total, err = h.db.Queries.CountSearchAircraft(ctx, searchTerm)
-		middleware.RecordDatabaseQuery("search_count", time.Since(countStart), err == nil)
-		
 		if err != nil {
 			middleware.RecordAircraftSearch(queryType, time.Since(start))
 			return c.JSON(http.StatusInternalServerError, ErrorResponse{
 				Error:   "database_error",
-				Message: "Failed to count search results",
+				Message: "Failed to count search facets",
 			})
 		}
 	}
@@ -156,18 +284,15 @@ func (h *Handlers) SearchAircraft(c echo.Context) error {
 	// Record successful search metrics
 	middleware.RecordAircraftSearch(queryType, time.Since(start))
 
-	response := SearchResponse{
-		Aircraft: aircraft,
-		Total:    total,
-		Page:     req.Page,
-		Limit:    req.Limit,
-	}
-
 	return c.JSON(http.StatusOK, response)
 }
 
 // GetAircraft handles GET /api/aircraft/:id
 func (h *Handlers) GetAircraft(c echo.Context) error {
+	return h.getAircraft(c, v1Representation)
+}
+
+func (h *Handlers) getAircraft(c echo.Context, rep representation) error {
 	start := time.Now()
 	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
 	defer cancel()
@@ -183,7 +308,20 @@ func (h *Handlers) GetAircraft(c echo.Context) error {
 		})
 	}
 
-	aircraft, err := h.db.Queries.GetAircraft(ctx, int32(id))
+	version, status, errResp := h.datasetVersion(ctx, c)
+	if errResp != nil {
+		middleware.RecordDatabaseQuery("get_by_id", time.Since(start), false)
+		return c.JSON(status, errResp)
+	}
+
+	var aircraft db.AircraftDatum
+	if version == 0 {
+		aircraft, err = h.db.Queries.GetAircraft(ctx, int32(id))
+	} else {
+		var row db.GetAircraftAtVersionRow
+		row, err = h.db.Queries.GetAircraftAtVersion(ctx, db.GetAircraftAtVersionParams{VersionID: version, ID: int32(id)})
+		aircraft = db.AircraftDatum(row)
+	}
 	middleware.RecordDatabaseQuery("get_by_id", time.Since(start), err == nil)
 	
 	if err != nil {
@@ -199,7 +337,117 @@ func (h *Handlers) GetAircraft(c echo.Context) error {
 		})
 	}
 
-	return c.JSON(http.StatusOK, aircraft)
+	return c.JSON(http.StatusOK, rep.one(aircraft, units.FromContext(ctx)))
+}
+
+// GetAircraftByICAO handles GET /api/aircraft/icao/:code
+func (h *Handlers) GetAircraftByICAO(c echo.Context) error {
+	return h.getAircraftByICAO(c, v1Representation)
+}
+
+func (h *Handlers) getAircraftByICAO(c echo.Context, rep representation) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	code := strings.TrimSpace(c.Param("code"))
+	if code == "" {
+		middleware.RecordDatabaseQuery("get_by_icao", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_code",
+			Message: "ICAO code is required",
+		})
+	}
+
+	version, status, errResp := h.datasetVersion(ctx, c)
+	if errResp != nil {
+		middleware.RecordDatabaseQuery("get_by_icao", time.Since(start), false)
+		return c.JSON(status, errResp)
+	}
+
+	var aircraft []db.AircraftDatum
+	var err error
+	if version == 0 {
+		aircraft, err = h.db.Queries.GetAircraftByICAOCode(ctx, code)
+	} else {
+		var rows []db.GetAircraftByICAOCodeAtVersionRow
+		rows, err = h.db.Queries.GetAircraftByICAOCodeAtVersion(ctx, db.GetAircraftByICAOCodeAtVersionParams{VersionID: version, Code: code})
+		for _, row := range rows {
+			aircraft = append(aircraft, db.AircraftDatum(row))
+		}
+	}
+	middleware.RecordDatabaseQuery("get_by_icao", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	if len(aircraft) == 0 {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: "No aircraft found for ICAO code " + code,
+		})
+	}
+
+	return c.JSON(http.StatusOK, rep.list(aircraft, units.FromContext(ctx)))
+}
+
+// GetAircraftByFAA handles GET /api/aircraft/faa/:designator
+func (h *Handlers) GetAircraftByFAA(c echo.Context) error {
+	return h.getAircraftByFAA(c, v1Representation)
+}
+
+func (h *Handlers) getAircraftByFAA(c echo.Context, rep representation) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	designator := strings.TrimSpace(c.Param("designator"))
+	if designator == "" {
+		middleware.RecordDatabaseQuery("get_by_faa", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_designator",
+			Message: "FAA designator is required",
+		})
+	}
+
+	version, status, errResp := h.datasetVersion(ctx, c)
+	if errResp != nil {
+		middleware.RecordDatabaseQuery("get_by_faa", time.Since(start), false)
+		return c.JSON(status, errResp)
+	}
+
+	var aircraft []db.AircraftDatum
+	var err error
+	if version == 0 {
+		aircraft, err = h.db.Queries.GetAircraftByFAADesignator(ctx, designator)
+	} else {
+		var rows []db.GetAircraftByFAADesignatorAtVersionRow
+		rows, err = h.db.Queries.GetAircraftByFAADesignatorAtVersion(ctx, db.GetAircraftByFAADesignatorAtVersionParams{VersionID: version, Designator: designator})
+		for _, row := range rows {
+			aircraft = append(aircraft, db.AircraftDatum(row))
+		}
+	}
+	middleware.RecordDatabaseQuery("get_by_faa", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+
+	if len(aircraft) == 0 {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: "No aircraft found for FAA designator " + designator,
+		})
+	}
+
+	return c.JSON(http.StatusOK, rep.list(aircraft, units.FromContext(ctx)))
 }
 
 // HealthCheck handles GET /api/health
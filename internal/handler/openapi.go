@@ -0,0 +1,517 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"reflect"
+	"sync"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/classify"
+	"github.com/dukerupert/faa-aircraft-search/internal/compare"
+	"github.com/dukerupert/faa-aircraft-search/internal/compat"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/diff"
+	"github.com/dukerupert/faa-aircraft-search/internal/dto"
+	"github.com/dukerupert/faa-aircraft-search/internal/openapi"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/dukerupert/faa-aircraft-search/internal/wake"
+	"github.com/labstack/echo/v4"
+)
+
+// OpenAPI returns the OpenAPI document describing every route registered in
+// internal/server/server.go. It is built once from the request and response
+// types, so adding a field to them updates the document; adding a route
+// means adding an operation here, which TestOpenAPICoversRoutes in
+// internal/server/server_test.go enforces.
+var OpenAPI = sync.OnceValue(buildOpenAPI)
+
+// OpenAPISpec handles GET /api/openapi.json
+func (h *Handlers) OpenAPISpec(c echo.Context) error {
+	return c.JSON(http.StatusOK, OpenAPI())
+}
+
+// APIDocs renders the interactive API reference. Its assets are served
+// from the same embedded directory under /api/docs/.
+func (h *Handlers) APIDocs(c echo.Context) error {
+	page, err := openapi.Docs.ReadFile("docs/index.html")
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Documentation unavailable")
+	}
+	return c.HTMLBlob(http.StatusOK, page)
+}
+
+// searchParamDocs describes the SearchRequest query parameters
+var searchParamDocs = map[string]*openapi.Schema{
+	"q":             openapi.String("Free text search over codes, manufacturer and model"),
+	"sort":          openapi.String("Comma separated sort keys such as mtow_lb:desc,wingspan_ft_with_winglets_sharklets. relevance must come first."),
+	"page":          {Type: "integer", Description: "Page number for offset pagination", Default: 1, Minimum: float(1)},
+	"limit":         {Type: "integer", Description: "Page size; out of range values fall back to 50", Default: 50, Minimum: float(1), Maximum: float(100)},
+	"cursor":        openapi.String("Opaque cursor from next_cursor or prev_cursor; replaces page"),
+	"include_total": {Type: "boolean", Description: "Count the matching aircraft", Default: true},
+	"format":        openapi.Enum("Export every matching aircraft as a file instead of a JSON page", "csv", "xlsx"),
+	"facets":        openapi.String("Comma separated columns to count per value or histogram bucket"),
+}
+
+func float(v float64) *float64 {
+	return &v
+}
+
+func buildOpenAPI() *openapi.Document {
+	doc := openapi.New(openapi.Info{
+		Title:   "FAA Aircraft Search API",
+		Version: "1.0.0",
+		Description: "Search the FAA Aircraft Characteristics Database. Aircraft, comparison, search and classification " +
+			"endpoints accept units=metric to convert dimensional fields; airport design endpoints also read their dimensions in it.",
+	},
+		openapi.Tag{Name: "Aircraft", Description: "Search and look up aircraft in the v1 representation, which serializes stored rows as they are"},
+		openapi.Tag{Name: "Aircraft v2", Description: "The same lookups returning the typed, grouped v2 representation"},
+		openapi.Tag{Name: "Datasets", Description: "Imported releases of the FAA Aircraft Characteristics Database"},
+		openapi.Tag{Name: "Classification", Description: "Aircraft approach category, airplane design group and taxiway design group"},
+		openapi.Tag{Name: "Airport design", Description: "Runway, taxiway and gate compatibility"},
+		openapi.Tag{Name: "Wake turbulence", Description: "Reference wake separation tables. Not for operational use."},
+		openapi.Tag{Name: "Service", Description: "Health, metrics and API documentation"},
+		openapi.Tag{Name: "Web", Description: "HTML pages and fragments of the web UI"},
+	)
+
+	errorResponse := doc.Schema(ErrorResponse{})
+	aircraftV1 := doc.Schema(units.Aircraft{})
+	aircraftV2 := doc.Named("AircraftV2", dto.Aircraft{}, nil)
+
+	respond := func(description string, schema *openapi.Schema) openapi.Response {
+		return openapi.Response{Description: description, Content: openapi.JSON(schema)}
+	}
+	fail := func(description string) openapi.Response {
+		return respond(description, errorResponse)
+	}
+	html := func(description string) openapi.Response {
+		return openapi.Response{Description: description, Content: map[string]openapi.MediaType{
+			"text/html": {Schema: openapi.String("")},
+		}}
+	}
+
+	unitsParam := openapi.QueryParam("units", "Unit system of dimensional fields, range filters and histogram facets; defaults to imperial. "+
+		"In metric, v1 fields swap the unit in their name, e.g. length_ft becomes length_m.", openapi.Enum("", string(units.Imperial), string(units.Metric)))
+	idParam := openapi.PathParam("id", "Database id of the aircraft. Ids change across re-imports; prefer codes.", openapi.Integer(""))
+	codeParam := openapi.PathParam("code", "ICAO type designator, matched case-insensitively", openapi.String(""))
+	designatorParam := openapi.PathParam("designator", "FAA designator, matched case-insensitively", openapi.String(""))
+	versionParam := openapi.QueryParam("version", "Dataset version id to read from instead of the live data, see /api/v1/versions", openapi.Integer(""))
+
+	searchParams := append(searchRequestParams(), filterParams()...)
+	searchParams = append(searchParams, versionParam, unitsParam)
+
+	searchResponses := func(aircraft *openapi.Schema, name string) map[string]openapi.Response {
+		schema := doc.Named(name, SearchResponse{}, func(s *openapi.Schema) {
+			s.Properties["aircraft"] = openapi.ArrayOf(aircraft)
+		})
+		return map[string]openapi.Response{
+			"200": {Description: "A page of aircraft, or the export file when format is set", Content: map[string]openapi.MediaType{
+				"application/json": {Schema: schema},
+				"text/csv":         {Schema: openapi.String("")},
+				"application/vnd.openxmlformats-officedocument.spreadsheetml.sheet": {Schema: openapi.Binary()},
+			}},
+			"400": fail("Invalid query, filter, sort, facet, cursor, format or version"),
+			"404": fail("The dataset version does not exist"),
+			"500": fail("Database error"),
+		}
+	}
+	lookupResponses := func(aircraft *openapi.Schema) map[string]openapi.Response {
+		return map[string]openapi.Response{
+			"200": respond("Every aircraft variant with the code", openapi.ArrayOf(aircraft)),
+			"400": fail("Missing code or invalid version"),
+			"404": fail("No aircraft has the code, or the dataset version does not exist"),
+			"500": fail("Database error"),
+		}
+	}
+	getResponses := func(aircraft *openapi.Schema) map[string]openapi.Response {
+		return map[string]openapi.Response{
+			"200": respond("The aircraft", aircraft),
+			"400": fail("Invalid id or version"),
+			"404": fail("No aircraft has the id, or the dataset version does not exist"),
+			"500": fail("Database error"),
+		}
+	}
+
+	// Aircraft v1
+	doc.Add(http.MethodGet, "/api/v1/aircraft/search", &openapi.Operation{
+		OperationID: "searchAircraft",
+		Tags:        []string{"Aircraft"},
+		Summary:     "Search aircraft with pagination",
+		Description: "Categorical columns filter by value, e.g. adg=III&aac=C,D. Numeric columns take inclusive min_ and max_ bounds.",
+		Parameters:  searchParams,
+		Responses:   searchResponses(aircraftV1, "SearchResponse"),
+	})
+	doc.Add(http.MethodPost, "/api/v1/aircraft/batch", &openapi.Operation{
+		OperationID: "batchLookup",
+		Tags:        []string{"Aircraft"},
+		Summary:     "Look up many ICAO codes, FAA designators and ids at once",
+		Parameters:  []openapi.Parameter{unitsParam},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(doc.Schema(BatchRequest{}))},
+		Responses: map[string]openapi.Response{
+			"200": respond("Matches per input and the unresolved inputs", doc.Schema(BatchResponse{})),
+			"400": fail("Empty or too large batch, or invalid body"),
+			"500": fail("Database error"),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/aircraft/compare", &openapi.Operation{
+		OperationID: "compareAircraft",
+		Tags:        []string{"Aircraft"},
+		Summary:     fmt.Sprintf("Compare %d to %d aircraft side by side", compare.MinAircraft, compare.MaxAircraft),
+		Parameters: []openapi.Parameter{
+			{Name: "ids", In: "query", Required: true, Description: "Comma separated or repeated aircraft ids", Schema: openapi.String("")},
+			unitsParam,
+		},
+		Responses: map[string]openapi.Response{
+			"200": respond("The aircraft and their aligned attributes", doc.Schema(compare.Comparison{})),
+			"400": fail("Invalid ids or wrong number of ids"),
+			"404": fail("Some ids do not exist"),
+			"500": fail("Database error"),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/aircraft/icao/:code", &openapi.Operation{
+		OperationID: "getAircraftByICAO",
+		Tags:        []string{"Aircraft"},
+		Summary:     "Get all aircraft with an ICAO type designator",
+		Parameters:  []openapi.Parameter{codeParam, versionParam, unitsParam},
+		Responses:   lookupResponses(aircraftV1),
+	})
+	doc.Add(http.MethodGet, "/api/v1/aircraft/faa/:designator", &openapi.Operation{
+		OperationID: "getAircraftByFAA",
+		Tags:        []string{"Aircraft"},
+		Summary:     "Get all aircraft with an FAA designator",
+		Parameters:  []openapi.Parameter{designatorParam, versionParam, unitsParam},
+		Responses:   lookupResponses(aircraftV1),
+	})
+	doc.Add(http.MethodGet, "/api/v1/aircraft/:id", &openapi.Operation{
+		OperationID: "getAircraft",
+		Tags:        []string{"Aircraft"},
+		Summary:     "Get specific aircraft by ID",
+		Parameters:  []openapi.Parameter{idParam, versionParam, unitsParam},
+		Responses:   getResponses(aircraftV1),
+	})
+
+	// Aircraft v2
+	doc.Add(http.MethodGet, "/api/v2/aircraft/search", &openapi.Operation{
+		OperationID: "searchAircraftV2",
+		Tags:        []string{"Aircraft v2"},
+		Summary:     "Search aircraft, returning the typed v2 representation",
+		Description: "Takes the same parameters as the v1 search.",
+		Parameters:  searchParams,
+		Responses:   searchResponses(aircraftV2, "SearchResponseV2"),
+	})
+	doc.Add(http.MethodGet, "/api/v2/aircraft/icao/:code", &openapi.Operation{
+		OperationID: "getAircraftByICAOV2",
+		Tags:        []string{"Aircraft v2"},
+		Summary:     "Get all aircraft with an ICAO type designator",
+		Parameters:  []openapi.Parameter{codeParam, versionParam, unitsParam},
+		Responses:   lookupResponses(aircraftV2),
+	})
+	doc.Add(http.MethodGet, "/api/v2/aircraft/faa/:designator", &openapi.Operation{
+		OperationID: "getAircraftByFAAV2",
+		Tags:        []string{"Aircraft v2"},
+		Summary:     "Get all aircraft with an FAA designator",
+		Parameters:  []openapi.Parameter{designatorParam, versionParam, unitsParam},
+		Responses:   lookupResponses(aircraftV2),
+	})
+	doc.Add(http.MethodGet, "/api/v2/aircraft/:id", &openapi.Operation{
+		OperationID: "getAircraftV2",
+		Tags:        []string{"Aircraft v2"},
+		Summary:     "Get specific aircraft by ID",
+		Parameters:  []openapi.Parameter{idParam, versionParam, unitsParam},
+		Responses:   getResponses(aircraftV2),
+	})
+
+	// Datasets
+	doc.Add(http.MethodGet, "/api/v1/versions", &openapi.Operation{
+		OperationID: "listVersions",
+		Tags:        []string{"Datasets"},
+		Summary:     "List the imported dataset versions, newest first",
+		Description: "Every import records a version holding a snapshot of the data it produced. Pass its id as version to the search and lookup endpoints to read that snapshot.",
+		Responses: map[string]openapi.Response{
+			"200": respond("The dataset versions", openapi.ArrayOf(doc.Schema(db.DatasetVersion{}))),
+			"500": fail("Database error"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/diff", &openapi.Operation{
+		OperationID: "diffRelease",
+		Tags:        []string{"Datasets"},
+		Summary:     "Compare an FAA release with the current data without importing it",
+		Description: fmt.Sprintf("Types are matched on ICAO code and FAA designator. The file may be in any import format, "+
+			"detected from its name or content, and is limited to %d MB.", MaxDiffUpload>>20),
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("format", "Report format", &openapi.Schema{Type: "string", Enum: []any{string(diff.FormatJSON), string(diff.FormatText), string(diff.FormatMarkdown)}, Default: string(diff.FormatJSON)}),
+		},
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			"multipart/form-data": {Schema: &openapi.Schema{Type: "object", Required: []string{"file"}, Properties: map[string]*openapi.Schema{
+				"file": openapi.Binary(),
+			}}},
+		}},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "Added, removed and modified types with their changed fields", Content: map[string]openapi.MediaType{
+				"application/json": {Schema: doc.Schema(diff.Report{})},
+				"text/plain":       {Schema: openapi.String("")},
+				"text/markdown":    {Schema: openapi.String("")},
+			}},
+			"400": fail("Invalid format, or missing or unreadable file"),
+			"413": fail("File too large"),
+			"500": fail("Database error"),
+		},
+	})
+
+	// Classification
+	doc.Add(http.MethodPost, "/api/v1/classify", &openapi.Operation{
+		OperationID: "classify",
+		Tags:        []string{"Classification"},
+		Summary:     "Derive AAC, ADG and TDG from aircraft dimensions",
+		Description: "Dimensions are read in the requested unit system.",
+		Parameters:  []openapi.Parameter{unitsParam},
+		RequestBody: &openapi.RequestBody{Required: true, Content: openapi.JSON(doc.Schema(classify.Input{}))},
+		Responses: map[string]openapi.Response{
+			"200": respond("The groups that could be derived", doc.Schema(classify.Classification{})),
+			"400": fail("No dimension given or invalid dimensions"),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/classify/mismatches", &openapi.Operation{
+		OperationID: "classificationMismatches",
+		Tags:        []string{"Classification"},
+		Summary:     "List aircraft whose stored AAC, ADG or TDG disagree with their dimensions",
+		Responses: map[string]openapi.Response{
+			"200": respond("The mismatching aircraft", doc.Schema(MismatchResponse{})),
+			"500": fail("Database error"),
+		},
+	})
+
+	// Airport design
+	doc.Add(http.MethodGet, "/api/v1/compatibility", &openapi.Operation{
+		OperationID: "compatibility",
+		Tags:        []string{"Airport design"},
+		Summary:     "Group aircraft by fit with a runway design code and taxiway design group",
+		Description: "At least one of rdc, aac, adg, tdg or taxiway_width_ft is required. The taxiway width is read and " +
+			"issues are reported in the requested unit system; in metric the fields swap the unit in their name.",
+		Parameters: []openapi.Parameter{
+			openapi.QueryParam("rdc", "Runway design code such as C-III; a trailing visibility minimum is ignored", openapi.String("")),
+			openapi.QueryParam("aac", "Aircraft approach category", openapi.Enum("", "A", "B", "C", "D", "E")),
+			openapi.QueryParam("adg", "Airplane design group", openapi.Enum("", "I", "II", "III", "IV", "V", "VI")),
+			openapi.QueryParam("tdg", "Taxiway design group", openapi.Enum("", "1A", "1B", "2A", "2B", "3", "4", "5", "6")),
+			openapi.QueryParam("taxiway_width_ft", "Taxiway width in the requested unit system", openapi.Number("")),
+			openapi.QueryParam("margin_pct", "How close to a limit, in percent, counts as marginal",
+				&openapi.Schema{Type: "number", Default: compat.DefaultMarginPct, Minimum: float(0), Maximum: float(50)}),
+			unitsParam,
+		},
+		Responses: map[string]openapi.Response{
+			"200": respond("Compatible, marginal and incompatible aircraft", doc.Schema(compat.Report{})),
+			"400": fail("Invalid or missing criteria"),
+			"500": fail("Database error"),
+		},
+	})
+	gateBody := &openapi.RequestBody{
+		Required:    true,
+		Description: "Gate envelopes as JSON, a CSV body or a multipart CSV upload in the file field, in the requested unit system",
+		Content: map[string]openapi.MediaType{
+			"application/json": {Schema: doc.Schema(GateRequest{})},
+			"text/csv":         {Schema: openapi.String("Columns id and max_wingspan_ft, max_length_ft, max_tail_height_ft")},
+			"multipart/form-data": {Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+				"file":                 openapi.Binary(),
+				"wingtip_clearance_ft": openapi.Number(""),
+			}}},
+		},
+	}
+	clearanceParam := openapi.QueryParam("wingtip_clearance_ft", "Wingtip clearance for CSV uploads, in the requested unit system", openapi.Number(""))
+	doc.Add(http.MethodPost, "/api/v1/gates/fit", &openapi.Operation{
+		OperationID: "gateFit",
+		Tags:        []string{"Airport design"},
+		Summary:     "List the aircraft that fit each gate envelope",
+		Description: "In metric, the fields of the response swap the unit in their name, e.g. max_wingspan_ft becomes max_wingspan_m.",
+		Parameters:  []openapi.Parameter{clearanceParam, unitsParam},
+		RequestBody: gateBody,
+		Responses: map[string]openapi.Response{
+			"200": respond("The fitting aircraft of each gate", doc.Schema(GateFitResponse{})),
+			"400": fail("Invalid or too many gates"),
+			"500": fail("Database error"),
+		},
+	})
+	doc.Add(http.MethodPost, "/api/v1/aircraft/:id/gates", &openapi.Operation{
+		OperationID: "aircraftGates",
+		Tags:        []string{"Airport design"},
+		Summary:     "List the gate envelopes an aircraft fits",
+		Description: "In metric, the fields of the response swap the unit in their name, e.g. max_wingspan_ft becomes max_wingspan_m.",
+		Parameters:  []openapi.Parameter{idParam, clearanceParam, unitsParam},
+		RequestBody: gateBody,
+		Responses: map[string]openapi.Response{
+			"200": respond("The gates the aircraft fits and does not fit", doc.Schema(AircraftGatesResponse{})),
+			"400": fail("Invalid id, invalid or too many gates"),
+			"404": fail("No aircraft has the id"),
+			"500": fail("Database error"),
+		},
+	})
+
+	// Wake turbulence
+	doc.Add(http.MethodGet, "/api/v1/wake/separation", &openapi.Operation{
+		OperationID: "wakeSeparation",
+		Tags:        []string{"Wake turbulence"},
+		Summary:     "In-trail wake separation between a leader and follower type",
+		Parameters: []openapi.Parameter{
+			{Name: "leader", In: "query", Required: true, Description: "ICAO or FAA code of the leading aircraft", Schema: openapi.String("")},
+			{Name: "follower", In: "query", Required: true, Description: "ICAO or FAA code of the following aircraft", Schema: openapi.String("")},
+			openapi.QueryParam("scheme", "Scheme id; every scheme when omitted", openapi.Enum("", schemeIDs()...)),
+			openapi.QueryParam("version", "Scheme version; the latest when omitted. Requires scheme.", openapi.String("")),
+		},
+		Responses: map[string]openapi.Response{
+			"200": respond("The separation under each scheme", doc.Schema(WakeSeparationResponse{})),
+			"400": fail("Missing types or invalid scheme"),
+			"404": fail("A type code does not exist"),
+			"500": fail("Database error"),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/v1/wake/schemes", &openapi.Operation{
+		OperationID: "wakeSchemes",
+		Tags:        []string{"Wake turbulence"},
+		Summary:     "List the embedded wake separation tables and their versions",
+		Responses: map[string]openapi.Response{
+			"200": respond("Every version of every scheme", openapi.ArrayOf(doc.Schema(wake.Scheme{}))),
+		},
+	})
+
+	// Service
+	doc.Add(http.MethodGet, "/health", &openapi.Operation{
+		OperationID: "healthCheck",
+		Tags:        []string{"Service"},
+		Summary:     "Health check and database status",
+		Responses: map[string]openapi.Response{
+			"200": respond("The service and database are healthy", doc.Schema(HealthResponse{})),
+			"503": fail("The database is unreachable"),
+		},
+	})
+	doc.Add(http.MethodGet, "/metrics", &openapi.Operation{
+		OperationID: "metrics",
+		Tags:        []string{"Service"},
+		Summary:     "Prometheus metrics",
+		Responses: map[string]openapi.Response{
+			"200": {Description: "Metrics in the Prometheus text format", Content: map[string]openapi.MediaType{
+				"text/plain": {Schema: openapi.String("")},
+			}},
+		},
+	})
+	doc.Add(http.MethodGet, "/api/openapi.json", &openapi.Operation{
+		OperationID: "openAPISpec",
+		Tags:        []string{"Service"},
+		Summary:     "This OpenAPI document",
+		Responses: map[string]openapi.Response{
+			"200": respond("The OpenAPI 3.1 document", &openapi.Schema{Type: "object"}),
+		},
+	})
+	doc.Add(http.MethodGet, "/api/docs", &openapi.Operation{
+		OperationID: "apiDocs",
+		Tags:        []string{"Service"},
+		Summary:     "Interactive API reference rendered from the OpenAPI document",
+		Responses:   map[string]openapi.Response{"200": html("The API reference page")},
+	})
+	doc.Add(http.MethodGet, "/api/docs/*", &openapi.Operation{
+		OperationID: "apiDocsAsset",
+		Tags:        []string{"Service"},
+		Summary:     "Scripts and styles of the API reference page",
+		Parameters:  []openapi.Parameter{openapi.PathParam("path", "Asset file name", openapi.String(""))},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "The asset"},
+			"404": {Description: "No such asset"},
+		},
+	})
+
+	// Web
+	pageParam := openapi.QueryParam("page", "Page number", openapi.Integer(""))
+	sortParam := openapi.QueryParam("sort", "Sort keys as in the search API", openapi.String(""))
+	web := []struct {
+		path, id, summary string
+		params            []openapi.Parameter
+	}{
+		{"/", "homePage", "Search page with the first page of aircraft", []openapi.Parameter{pageParam, sortParam}},
+		{"/search", "searchFragment", "Search results fragment for HTMX; takes the search API parameters", searchParams},
+		{"/aircraft-list", "aircraftListFragment", "Paginated aircraft list fragment for HTMX; redirects to / otherwise", []openapi.Parameter{pageParam, sortParam}},
+		{"/aircraft-details/:id", "aircraftDetailsFragment", "Aircraft details fragment", []openapi.Parameter{idParam}},
+		{"/compare", "comparePage", "Side-by-side comparison page", []openapi.Parameter{
+			{Name: "ids", In: "query", Required: true, Description: "Comma separated or repeated aircraft ids", Schema: openapi.String("")},
+		}},
+		{"/compatibility", "compatibilityPage", "Runway and taxiway compatibility checker; takes the compatibility API parameters", nil},
+		{"/wake", "wakeMatrixPage", "Wake separation matrix page", []openapi.Parameter{
+			openapi.QueryParam("scheme", "Scheme id", openapi.Enum("", schemeIDs()...)),
+			openapi.QueryParam("types", fmt.Sprintf("Up to %d comma separated type codes", wake.MaxMatrixTypes), openapi.String("")),
+		}},
+	}
+	for _, page := range web {
+		doc.Add(http.MethodGet, page.path, &openapi.Operation{
+			OperationID: page.id,
+			Tags:        []string{"Web"},
+			Summary:     page.summary,
+			Parameters:  page.params,
+			Responses:   map[string]openapi.Response{"200": html("The rendered HTML")},
+		})
+	}
+	doc.Add(http.MethodPost, "/units", &openapi.Operation{
+		OperationID: "setUnits",
+		Tags:        []string{"Web"},
+		Summary:     "Remember the unit system of the web UI in a cookie",
+		RequestBody: &openapi.RequestBody{Required: true, Content: map[string]openapi.MediaType{
+			"application/x-www-form-urlencoded": {Schema: &openapi.Schema{Type: "object", Properties: map[string]*openapi.Schema{
+				"units": openapi.Enum("", string(units.Imperial), string(units.Metric)),
+			}}},
+		}},
+		Responses: map[string]openapi.Response{
+			"303": {Description: "Redirect back to the referring page"},
+			"400": {Description: "Invalid unit system"},
+		},
+	})
+	doc.Add(http.MethodGet, "/static*", &openapi.Operation{
+		OperationID: "staticFile",
+		Tags:        []string{"Web"},
+		Summary:     "Static assets of the web UI",
+		Parameters:  []openapi.Parameter{openapi.PathParam("path", "File path below /static", openapi.String(""))},
+		Responses: map[string]openapi.Response{
+			"200": {Description: "The file"},
+			"404": {Description: "No such file"},
+		},
+	})
+
+	return doc
+}
+
+// searchRequestParams describes the query parameters bound to SearchRequest
+func searchRequestParams() []openapi.Parameter {
+	t := reflect.TypeOf(SearchRequest{})
+	params := make([]openapi.Parameter, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("query")
+		doc, ok := searchParamDocs[name]
+		if !ok {
+			panic("handler: SearchRequest parameter " + name + " is not documented")
+		}
+		schema := *doc
+		schema.Description = ""
+		params = append(params, openapi.Parameter{Name: name, In: "query", Description: doc.Description, Schema: &schema})
+	}
+	return params
+}
+
+// filterParams describes the attribute filters read by search.ParseFilter
+func filterParams() []openapi.Parameter {
+	var params []openapi.Parameter
+	for _, col := range search.Columns {
+		switch {
+		case col.Categorical:
+			params = append(params, openapi.QueryParam(col.Name, "Comma separated or repeated values of "+col.Name, openapi.String("")))
+		case col.Kind != search.KindText:
+			params = append(params,
+				openapi.QueryParam("min_"+col.Name, "Inclusive lower bound of "+col.Name, openapi.Number("")),
+				openapi.QueryParam("max_"+col.Name, "Inclusive upper bound of "+col.Name, openapi.Number("")))
+		}
+	}
+	return params
+}
+
+// schemeIDs lists the ids of the embedded wake schemes
+func schemeIDs() []string {
+	var ids []string
+	for _, s := range wake.Schemes() {
+		ids = append(ids, s.ID)
+	}
+	return ids
+}
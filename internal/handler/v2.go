@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/dto"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/labstack/echo/v4"
+)
+
+// representation renders aircraft as the JSON of one API version
+type representation struct {
+	one  func(db.AircraftDatum, units.System) any
+	list func([]db.AircraftDatum, units.System) any
+}
+
+// v1Representation serves the stored columns as they are, plus unit labels
+var v1Representation = representation{
+	one:  func(a db.AircraftDatum, s units.System) any { return units.NewAircraft(a, s) },
+	list: func(a []db.AircraftDatum, s units.System) any { return units.NewAircraftList(a, s) },
+}
+
+// v2Representation serves the typed and grouped dto.Aircraft
+var v2Representation = representation{
+	one:  func(a db.AircraftDatum, s units.System) any { return dto.FromAircraft(a, s) },
+	list: func(a []db.AircraftDatum, s units.System) any { return dto.FromAircraftList(a, s) },
+}
+
+// SearchAircraftV2 handles GET /api/v2/aircraft/search
+func (h *Handlers) SearchAircraftV2(c echo.Context) error {
+	return h.searchAircraft(c, v2Representation)
+}
+
+// GetAircraftV2 handles GET /api/v2/aircraft/:id
+func (h *Handlers) GetAircraftV2(c echo.Context) error {
+	return h.getAircraft(c, v2Representation)
+}
+
+// GetAircraftByICAOV2 handles GET /api/v2/aircraft/icao/:code
+func (h *Handlers) GetAircraftByICAOV2(c echo.Context) error {
+	return h.getAircraftByICAO(c, v2Representation)
+}
+
+// GetAircraftByFAAV2 handles GET /api/v2/aircraft/faa/:designator
+func (h *Handlers) GetAircraftByFAAV2(c echo.Context) error {
+	return h.getAircraftByFAA(c, v2Representation)
+}
@@ -0,0 +1,67 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/jackc/pgx/v5"
+	"github.com/labstack/echo/v4"
+)
+
+// ListVersions handles GET /api/v1/versions
+func (h *Handlers) ListVersions(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	versions, err := h.db.Queries.ListDatasetVersions(ctx)
+	middleware.RecordDatabaseQuery("list_versions", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve dataset versions",
+		})
+	}
+
+	return c.JSON(http.StatusOK, versions)
+}
+
+// datasetVersion reads the optional version parameter selecting a dataset
+// snapshot. It returns 0 for the live data, or the status and body of the
+// error response when the version is invalid or unknown.
+func (h *Handlers) datasetVersion(ctx context.Context, c echo.Context) (int32, int, *ErrorResponse) {
+	raw := strings.TrimSpace(c.QueryParam("version"))
+	if raw == "" {
+		return 0, 0, nil
+	}
+
+	id, err := strconv.ParseInt(raw, 10, 32)
+	if err != nil || id < 1 {
+		return 0, http.StatusBadRequest, &ErrorResponse{
+			Error:   "invalid_version",
+			Message: fmt.Sprintf("Invalid version %q: must be a dataset version id", raw),
+		}
+	}
+
+	if _, err := h.db.Queries.GetDatasetVersion(ctx, int32(id)); err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return 0, http.StatusNotFound, &ErrorResponse{
+				Error:   "version_not_found",
+				Message: fmt.Sprintf("Dataset version %d does not exist", id),
+			}
+		}
+		return 0, http.StatusInternalServerError, &ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve dataset version",
+		}
+	}
+
+	return int32(id), 0, nil
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/wake"
+	"github.com/dukerupert/faa-aircraft-search/web/templates/pages"
+	"github.com/labstack/echo/v4"
+)
+
+// WakeSeparationResponse holds the separation of a pair of types under
+// each requested scheme
+type WakeSeparationResponse struct {
+	Leader      string        `json:"leader"`
+	Follower    string        `json:"follower"`
+	Separations []wake.Result `json:"separations"`
+}
+
+// WakeSeparation handles GET /api/wake/separation
+func (h *Handlers) WakeSeparation(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	leader := strings.ToUpper(strings.TrimSpace(c.QueryParam("leader")))
+	follower := strings.ToUpper(strings.TrimSpace(c.QueryParam("follower")))
+	if leader == "" || follower == "" {
+		middleware.RecordDatabaseQuery("wake_separation", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_request",
+			Message: "Both leader and follower type codes are required",
+		})
+	}
+
+	schemes, err := wakeSchemes(c.QueryParam("scheme"), c.QueryParam("version"))
+	if err != nil {
+		middleware.RecordDatabaseQuery("wake_separation", time.Since(start), false)
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Error:   "invalid_scheme",
+			Message: err.Error(),
+		})
+	}
+
+	aircraft, missing, err := h.aircraftByCodes(ctx, []string{leader, follower})
+	middleware.RecordDatabaseQuery("wake_separation", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Error:   "database_error",
+			Message: "Failed to retrieve aircraft data",
+		})
+	}
+	if len(missing) > 0 {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Error:   "not_found",
+			Message: "No aircraft found for " + strings.Join(missing, ", "),
+		})
+	}
+
+	response := WakeSeparationResponse{Leader: leader, Follower: follower}
+	for _, s := range schemes {
+		response.Separations = append(response.Separations, s.Pair(aircraft[leader], aircraft[follower]))
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
+
+// WakeSchemes handles GET /api/wake/schemes
+func (h *Handlers) WakeSchemes(c echo.Context) error {
+	return c.JSON(http.StatusOK, wake.Versions())
+}
+
+// WakeMatrix renders the separation matrix page for a set of types
+func (h *Handlers) WakeMatrix(c echo.Context) error {
+	start := time.Now()
+	ctx, cancel := context.WithTimeout(c.Request().Context(), 5*time.Second)
+	defer cancel()
+
+	id := c.QueryParam("scheme")
+	if id == "" {
+		id = wake.DefaultScheme
+	}
+	scheme, err := wake.Lookup(id, "")
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	types := parseTypeCodes(c.QueryParam("types"))
+	if len(types) == 0 {
+		return pages.Wake(wake.Schemes(), scheme, c.QueryParam("types"), nil, "").Render(ctx, c.Response().Writer)
+	}
+	if len(types) > wake.MaxMatrixTypes {
+		msg := fmt.Sprintf("At most %d types can be compared", wake.MaxMatrixTypes)
+		return pages.Wake(wake.Schemes(), scheme, c.QueryParam("types"), nil, msg).Render(ctx, c.Response().Writer)
+	}
+
+	aircraft, missing, err := h.aircraftByCodes(ctx, types)
+	middleware.RecordDatabaseQuery("wake_matrix", time.Since(start), err == nil)
+
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Database error")
+	}
+	if len(missing) > 0 {
+		msg := "No aircraft found for " + strings.Join(missing, ", ")
+		return pages.Wake(wake.Schemes(), scheme, c.QueryParam("types"), nil, msg).Render(ctx, c.Response().Writer)
+	}
+
+	matrix := wake.NewMatrix(scheme, types, aircraft)
+	return pages.Wake(wake.Schemes(), scheme, c.QueryParam("types"), &matrix, "").Render(ctx, c.Response().Writer)
+}
+
+// wakeSchemes returns the requested scheme version, or the latest version
+// of every scheme when none is named
+func wakeSchemes(id, version string) ([]wake.Scheme, error) {
+	if id == "" {
+		if version != "" {
+			return nil, fmt.Errorf("version requires a scheme")
+		}
+		return wake.Schemes(), nil
+	}
+
+	s, err := wake.Lookup(id, version)
+	if err != nil {
+		return nil, err
+	}
+	return []wake.Scheme{s}, nil
+}
+
+// parseTypeCodes splits a comma or space separated list of type codes,
+// upper-casing them and dropping duplicates
+func parseTypeCodes(raw string) []string {
+	var codes []string
+	seen := map[string]bool{}
+	for _, code := range strings.FieldsFunc(raw, func(r rune) bool { return r == ',' || r == ' ' }) {
+		code = strings.ToUpper(code)
+		if !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	return codes
+}
+
+// aircraftByCodes fetches the variants of upper-case type codes, matching
+// ICAO codes first and FAA designators otherwise, and returns the codes
+// that match nothing
+func (h *Handlers) aircraftByCodes(ctx context.Context, codes []string) (map[string][]db.AircraftDatum, []string, error) {
+	rows, err := h.db.Queries.GetAircraftBatch(ctx, db.GetAircraftBatchParams{
+		IcaoCodes:      codes,
+		FaaDesignators: codes,
+		Ids:            []int32{},
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	byICAO := map[string][]db.AircraftDatum{}
+	byFAA := map[string][]db.AircraftDatum{}
+	for _, a := range rows {
+		icao := strings.ToUpper(a.IcaoCode.String)
+		byICAO[icao] = append(byICAO[icao], a)
+		faa := strings.ToUpper(a.FaaDesignator.String)
+		byFAA[faa] = append(byFAA[faa], a)
+	}
+
+	aircraft := make(map[string][]db.AircraftDatum, len(codes))
+	var missing []string
+	for _, code := range codes {
+		switch {
+		case len(byICAO[code]) > 0:
+			aircraft[code] = byICAO[code]
+		case len(byFAA[code]) > 0:
+			aircraft[code] = byFAA[code]
+		default:
+			missing = append(missing, code)
+		}
+	}
+
+	return aircraft, missing, nil
+}
@@ -3,12 +3,14 @@ package handler
 import (
 	"context"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 
-	"github.com/dukerupert/faa-aircraft-search/internal/db"
 	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/search"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
 	"github.com/dukerupert/faa-aircraft-search/web/templates/components"
 	"github.com/dukerupert/faa-aircraft-search/web/templates/pages"
 	"github.com/jackc/pgx/v5"
@@ -32,8 +34,10 @@ func (h *Handlers) Home(c echo.Context) error {
 	limit := 10
 	offset := int32((page - 1) * limit)
 
-	// Get aircraft with pagination
-	aircraft, err := h.db.Queries.GetAllAircraft(ctx, db.GetAllAircraftParams{
+	// Get aircraft with pagination in the requested order
+	sort := sortParam(c)
+	aircraft, err := search.Aircraft(ctx, h.db.Pool, search.Query{
+		Sort:   sort,
 		Limit:  int32(limit),
 		Offset: offset,
 	})
@@ -47,7 +51,7 @@ func (h *Handlers) Home(c echo.Context) error {
 		return c.String(http.StatusInternalServerError, "Database error")
 	}
 
-	return pages.Home(aircraft, total, page, limit).Render(ctx, c.Response().Writer)
+	return pages.Home(aircraft, total, page, limit, sort.String()).Render(ctx, c.Response().Writer)
 }
 
 // Search handles HTMX search requests
@@ -56,6 +60,12 @@ func (h *Handlers) Search(c echo.Context) error {
 	defer cancel()
 
 	query := strings.TrimSpace(c.QueryParam("q"))
+
+	filter, err := search.ParseFilter(c.QueryParams())
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+	sort := sortParam(c)
 	
 	// Get page parameter, default to 1
 	page := 1
@@ -69,10 +79,11 @@ func (h *Handlers) Search(c echo.Context) error {
 	limit := 10
 	offset := int32((page - 1) * limit)
 	
-	// If query is empty, return to all aircraft view
-	if query == "" {
-		// Get aircraft with pagination
-		aircraft, err := h.db.Queries.GetAllAircraft(ctx, db.GetAllAircraftParams{
+	// If query and filters are empty, return to all aircraft view
+	if query == "" && filter.IsEmpty() {
+		// Get aircraft with pagination in the requested order
+		aircraft, err := search.Aircraft(ctx, h.db.Pool, search.Query{
+			Sort:   sort,
 			Limit:  int32(limit),
 			Offset: offset,
 		})
@@ -86,28 +97,37 @@ func (h *Handlers) Search(c echo.Context) error {
 			return c.String(http.StatusInternalServerError, "Database error")
 		}
 
-		return components.AircraftContainer(aircraft, total, page, limit).Render(ctx, c.Response().Writer)
+		return components.AircraftContainer(aircraft, total, page, limit, sort.String()).Render(ctx, c.Response().Writer)
 	}
 
 	// Search aircraft with pagination
-	searchTerm := "%" + strings.ToUpper(query) + "%"
-	
-	aircraft, err := h.db.Queries.SearchAircraft(ctx, db.SearchAircraftParams{
-		SearchTerm: searchTerm,
-		Limit:      int32(limit),
-		Offset:     offset,
-	})
+	searchQuery := search.Query{
+		Text:   query,
+		Filter: filter,
+		Sort:   sort,
+		Limit:  int32(limit),
+		Offset: offset,
+		Units:  units.FromContext(ctx),
+	}
+
+	aircraft, err := search.Aircraft(ctx, h.db.Pool, searchQuery)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Database error")
 	}
 
 	// Get total count for the search
-	total, err := h.db.Queries.CountSearchAircraft(ctx, searchTerm)
+	total, err := search.Count(ctx, h.db.Pool, searchQuery)
 	if err != nil {
 		return c.String(http.StatusInternalServerError, "Database error")
 	}
 
-	return components.SearchResults(aircraft, total, query, page, limit).Render(ctx, c.Response().Writer)
+	// Count the sidebar facets for the search
+	facets, err := search.Facets(ctx, h.db.Pool, searchQuery, components.FacetNames())
+	if err != nil {
+		return c.String(http.StatusInternalServerError, "Database error")
+	}
+
+	return components.SearchResults(aircraft, total, query, c.QueryParams(), page, limit, facets).Render(ctx, c.Response().Writer)
 }
 
 // AircraftList handles HTMX requests for paginated aircraft list
@@ -127,8 +147,10 @@ func (h *Handlers) AircraftList(c echo.Context) error {
 	limit := 10
 	offset := int32((page - 1) * limit)
 
-	// Get aircraft with pagination
-	aircraft, err := h.db.Queries.GetAllAircraft(ctx, db.GetAllAircraftParams{
+	// Get aircraft with pagination in the requested order
+	sort := sortParam(c)
+	aircraft, err := search.Aircraft(ctx, h.db.Pool, search.Query{
+		Sort:   sort,
 		Limit:  int32(limit),
 		Offset: offset,
 	})
@@ -145,11 +167,16 @@ func (h *Handlers) AircraftList(c echo.Context) error {
 	// Check if this is an HTMX request
 	if c.Request().Header.Get("HX-Request") == "true" {
 		// Return the aircraft container for HTMX requests
-		return components.AircraftContainer(aircraft, total, page, limit).Render(ctx, c.Response().Writer)
+		return components.AircraftContainer(aircraft, total, page, limit, sort.String()).Render(ctx, c.Response().Writer)
 	}
 
-	// For non-HTMX requests, redirect to home with page parameter
-	return c.Redirect(http.StatusSeeOther, "/?page="+strconv.Itoa(page))
+	// For non-HTMX requests, redirect to home with page and sort parameters
+	redirect := url.Values{}
+	redirect.Set("page", strconv.Itoa(page))
+	if len(sort) > 0 {
+		redirect.Set("sort", sort.String())
+	}
+	return c.Redirect(http.StatusSeeOther, "/?"+redirect.Encode())
 }
 
 // AircraftDetails handles GET /aircraft-details/:id
@@ -179,5 +206,41 @@ func (h *Handlers) AircraftDetails(c echo.Context) error {
 	// Record detail view metric
 	middleware.RecordAircraftDetailView()
 
+	aircraft = units.Convert(aircraft, units.FromContext(ctx))
 	return components.AircraftDetails(aircraft).Render(ctx, c.Response().Writer)
-}
\ No newline at end of file
+}
+
+// SetUnits handles POST /units, remembering the unit system of the web UI
+// in a cookie and returning to the page the toggle was used on
+func (h *Handlers) SetUnits(c echo.Context) error {
+	system, err := units.Parse(c.FormValue("units"))
+	if err != nil {
+		return c.String(http.StatusBadRequest, err.Error())
+	}
+
+	c.SetCookie(&http.Cookie{
+		Name:     units.CookieName,
+		Value:    string(system),
+		Path:     "/",
+		MaxAge:   365 * 24 * 60 * 60,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+
+	// Only redirect within the site
+	target := "/"
+	if ref, err := url.Parse(c.Request().Referer()); err == nil && strings.HasPrefix(ref.Path, "/") && !strings.HasPrefix(ref.Path, "//") {
+		target = ref.RequestURI()
+	}
+	return c.Redirect(http.StatusSeeOther, target)
+}
+
+// sortParam reads the optional sort parameter for web pages.
+// Like the page parameter, invalid values fall back to the default order.
+func sortParam(c echo.Context) search.Sort {
+	sort, err := search.ParseSort(c.QueryParam("sort"))
+	if err != nil {
+		return nil
+	}
+	return sort
+}
@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/labstack/echo/v4"
+)
+
+// Units returns an Echo middleware that stores the unit system of a request
+// in its context. The units query parameter wins over the cookie set by the
+// web UI; an invalid cookie falls back to imperial. The cookie only applies
+// to the HTML views, so API clients get metric only when they ask for it.
+// An invalid units parameter is answered by invalid on API paths, while
+// HTML views ignore it like their other invalid parameters.
+func Units(invalid func(c echo.Context, err error) error) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			api := strings.HasPrefix(c.Request().URL.Path, "/api/")
+
+			system := units.Imperial
+			if !api {
+				if cookie, err := c.Cookie(units.CookieName); err == nil {
+					if s, err := units.Parse(cookie.Value); err == nil {
+						system = s
+					}
+				}
+			}
+
+			if raw := c.QueryParam("units"); raw != "" {
+				s, err := units.Parse(raw)
+				switch {
+				case err == nil:
+					system = s
+				case api:
+					return invalid(c, err)
+				}
+			}
+
+			req := c.Request()
+			c.SetRequest(req.WithContext(units.WithSystem(req.Context(), system)))
+			return next(c)
+		}
+	}
+}
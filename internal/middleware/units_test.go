@@ -0,0 +1,60 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/labstack/echo/v4"
+)
+
+// serveUnits runs a request through the middleware and returns the status
+// and the unit system the handler saw
+func serveUnits(t *testing.T, target string, cookie string) (int, units.System) {
+	t.Helper()
+	e := echo.New()
+	e.Use(Units(func(c echo.Context, err error) error {
+		return c.NoContent(http.StatusBadRequest)
+	}))
+
+	var seen units.System
+	handler := func(c echo.Context) error {
+		seen = units.FromContext(c.Request().Context())
+		return c.NoContent(http.StatusOK)
+	}
+	e.GET("/search", handler)
+	e.GET("/api/v1/aircraft/search", handler)
+
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	if cookie != "" {
+		req.AddCookie(&http.Cookie{Name: units.CookieName, Value: cookie})
+	}
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+	return rec.Code, seen
+}
+
+func TestUnits(t *testing.T) {
+	for _, tt := range []struct {
+		name, target, cookie string
+		status               int
+		system               units.System
+	}{
+		{"default", "/api/v1/aircraft/search", "", http.StatusOK, units.Imperial},
+		{"api parameter", "/api/v1/aircraft/search?units=metric", "", http.StatusOK, units.Metric},
+		{"api ignores cookie", "/api/v1/aircraft/search", "metric", http.StatusOK, units.Imperial},
+		{"api invalid", "/api/v1/aircraft/search?units=furlongs", "", http.StatusBadRequest, ""},
+		{"html cookie", "/search", "metric", http.StatusOK, units.Metric},
+		{"html parameter wins", "/search?units=imperial", "metric", http.StatusOK, units.Imperial},
+		{"html invalid keeps cookie", "/search?units=furlongs", "metric", http.StatusOK, units.Metric},
+		{"html invalid", "/search?units=furlongs", "", http.StatusOK, units.Imperial},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			status, system := serveUnits(t, tt.target, tt.cookie)
+			if status != tt.status || system != tt.system {
+				t.Fatalf("expected %d %q, got %d %q", tt.status, tt.system, status, system)
+			}
+		})
+	}
+}
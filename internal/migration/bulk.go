@@ -0,0 +1,248 @@
+package migration
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/database"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/jackc/pgx/v5"
+)
+
+// importTable is the temporary table the bulk path copies rows into. It
+// is dropped when the import transaction ends.
+const importTable = "aircraft_import"
+
+// copyColumns are the aircraft_data columns an import writes, in the
+// order of Headers
+var copyColumns = []string{
+	"icao_code", "faa_designator", "manufacturer", "model_faa", "model_bada",
+	"physical_class_engine", "num_engines", "aac", "aac_minimum", "aac_maximum",
+	"adg", "tdg", "approach_speed_knot", "approach_speed_minimum_knot", "approach_speed_maximum_knot",
+	"wingspan_ft_without_winglets_sharklets", "wingspan_ft_with_winglets_sharklets",
+	"length_ft", "tail_height_at_oew_ft", "wheelbase_ft", "cockpit_to_main_gear_ft",
+	"main_gear_width_ft", "mtow_lb", "malw_lb", "main_gear_config", "icao_wtc",
+	"parking_area_ft2", "class", "faa_weight", "cwt", "one_half_wake_category",
+	"two_wake_category_appx_a", "two_wake_category_appx_b", "rotor_diameter_ft",
+	"srs", "lahso", "faa_registry", "registration_count", "tmfs_operations_fy24",
+	"remarks", "last_update",
+}
+
+// MergeCounts tells how a bulk load changed aircraft_data
+type MergeCounts struct {
+	Inserted  int `json:"inserted"`
+	Updated   int `json:"updated"`
+	Unchanged int `json:"unchanged"`
+	Deleted   int `json:"deleted"`
+}
+
+// bulkLoad streams the rows into a temporary table with COPY and merges
+// them into aircraft_data with a single upsert. Aircraft whose ICAO code
+// and FAA designator no row has are deleted first, and rows whose values
+// already match are left alone, so their updated_at is kept. It must run
+// in a transaction.
+func bulkLoad(ctx context.Context, tx pgx.Tx, rows []AircraftData) (MergeCounts, error) {
+	_, err := tx.Exec(ctx, fmt.Sprintf("CREATE TEMP TABLE %s ON COMMIT DROP AS SELECT %s FROM aircraft_data WITH NO DATA",
+		importTable, strings.Join(copyColumns, ", ")))
+	if err != nil {
+		return MergeCounts{}, fmt.Errorf("failed to create import table: %w", err)
+	}
+
+	rows = dedupeRows(rows)
+	_, err = tx.CopyFrom(ctx, pgx.Identifier{importTable}, copyColumns, pgx.CopyFromSlice(len(rows), func(i int) ([]any, error) {
+		return copyValues(upsertParams(rows[i])), nil
+	}))
+	if err != nil {
+		return MergeCounts{}, fmt.Errorf("failed to copy rows: %w", err)
+	}
+
+	// Rows without both codes never match, so they are replaced like in the
+	// row by row path
+	deleted, err := tx.Exec(ctx, fmt.Sprintf(`DELETE FROM aircraft_data a WHERE NOT EXISTS (
+    SELECT 1 FROM %s i WHERE i.icao_code = a.icao_code AND i.faa_designator = a.faa_designator
+)`, importTable))
+	if err != nil {
+		return MergeCounts{}, fmt.Errorf("failed to delete aircraft missing from the source: %w", err)
+	}
+
+	counts := MergeCounts{Deleted: int(deleted.RowsAffected())}
+	if err := tx.QueryRow(ctx, mergeSQL()).Scan(&counts.Inserted, &counts.Updated); err != nil {
+		return MergeCounts{}, fmt.Errorf("failed to merge rows: %w", err)
+	}
+	counts.Unchanged = len(rows) - counts.Inserted - counts.Updated
+	return counts, nil
+}
+
+// dedupeRows keeps the last of the rows sharing ICAO code and FAA
+// designator, since one upsert statement cannot change a row twice. This
+// matches the row by row path, where the later row overwrites the earlier.
+// Rows missing a code never conflict and are all kept.
+func dedupeRows(rows []AircraftData) []AircraftData {
+	type key struct{ icao, faa string }
+	last := map[key]int{}
+	for i, a := range rows {
+		if a.ICAOCode != "" && a.FAADesignator != "" {
+			last[key{a.ICAOCode, a.FAADesignator}] = i
+		}
+	}
+	if len(last) == len(rows) {
+		return rows
+	}
+
+	kept := make([]AircraftData, 0, len(rows))
+	for i, a := range rows {
+		if a.ICAOCode != "" && a.FAADesignator != "" && last[key{a.ICAOCode, a.FAADesignator}] != i {
+			continue
+		}
+		kept = append(kept, a)
+	}
+	return kept
+}
+
+// mergeSQL upserts the import table into aircraft_data and counts the
+// inserted and updated rows. A row is inserted when xmax is 0; rows with
+// unchanged values are skipped by the WHERE of the update and not
+// returned.
+func mergeSQL() string {
+	columns := strings.Join(copyColumns, ", ")
+	var set, old, excluded []string
+	for _, c := range copyColumns[2:] {
+		set = append(set, c+" = EXCLUDED."+c)
+		old = append(old, "aircraft_data."+c)
+		excluded = append(excluded, "EXCLUDED."+c)
+	}
+
+	return fmt.Sprintf(`WITH merged AS (
+    INSERT INTO aircraft_data (%[1]s)
+    SELECT %[1]s FROM %[2]s
+    ON CONFLICT (icao_code, faa_designator) DO UPDATE SET
+        %[3]s,
+        updated_at = CURRENT_TIMESTAMP
+    WHERE (%[4]s) IS DISTINCT FROM (%[5]s)
+    RETURNING xmax = 0 AS inserted
+)
+SELECT COUNT(*) FILTER (WHERE inserted), COUNT(*) FILTER (WHERE NOT inserted) FROM merged`,
+		columns, importTable, strings.Join(set, ",\n        "), strings.Join(old, ", "), strings.Join(excluded, ", "))
+}
+
+// copyValues returns the upsert parameters in the order of copyColumns
+func copyValues(p db.UpsertAircraftDataParams) []any {
+	return []any{
+		p.IcaoCode,
+		p.FaaDesignator,
+		p.Manufacturer,
+		p.ModelFaa,
+		p.ModelBada,
+		p.PhysicalClassEngine,
+		p.NumEngines,
+		p.Aac,
+		p.AacMinimum,
+		p.AacMaximum,
+		p.Adg,
+		p.Tdg,
+		p.ApproachSpeedKnot,
+		p.ApproachSpeedMinimumKnot,
+		p.ApproachSpeedMaximumKnot,
+		p.WingspanFtWithoutWingletsSharklets,
+		p.WingspanFtWithWingletsSharklets,
+		p.LengthFt,
+		p.TailHeightAtOewFt,
+		p.WheelbaseFt,
+		p.CockpitToMainGearFt,
+		p.MainGearWidthFt,
+		p.MtowLb,
+		p.MalwLb,
+		p.MainGearConfig,
+		p.IcaoWtc,
+		p.ParkingAreaFt2,
+		p.Class,
+		p.FaaWeight,
+		p.Cwt,
+		p.OneHalfWakeCategory,
+		p.TwoWakeCategoryAppxA,
+		p.TwoWakeCategoryAppxB,
+		p.RotorDiameterFt,
+		p.Srs,
+		p.Lahso,
+		p.FaaRegistry,
+		p.RegistrationCount,
+		p.TmfsOperationsFy24,
+		p.Remarks,
+		p.LastUpdate,
+	}
+}
+
+// benchmarkRuns is how often Benchmark runs each import path
+const benchmarkRuns = 3
+
+// Timing is the fastest run of an import path in a benchmark
+type Timing struct {
+	Path     string
+	Rows     int
+	Duration time.Duration
+}
+
+// Benchmark times the row by row and the bulk import of a source. Every run
+// happens in a transaction that is rolled back, so the data is left as it
+// is. Each path runs benchmarkRuns times and the fastest run counts;
+// reading the source is not timed.
+func Benchmark(ctx context.Context, database *database.Database, src *Source, opts Options) ([]Timing, error) {
+	rows, err := readSource(src, opts, &Report{})
+	if err != nil {
+		return nil, err
+	}
+
+	paths := importPaths(ctx, rows, src.firstRow())
+	timings := make([]Timing, len(paths))
+	for run := 0; run < benchmarkRuns; run++ {
+		for i, p := range paths {
+			d, err := timeLoad(ctx, database, p.load)
+			if err != nil {
+				return nil, fmt.Errorf("%s import failed: %w", p.name, err)
+			}
+			if run == 0 || d < timings[i].Duration {
+				timings[i] = Timing{Path: p.name, Rows: len(rows), Duration: d}
+			}
+		}
+	}
+	return timings, nil
+}
+
+// importPath is a way of writing rows to aircraft_data inside a
+// transaction
+type importPath struct {
+	name string
+	load func(pgx.Tx, *db.Queries) error
+}
+
+// importPaths returns the row by row and the bulk path for the rows. Row i
+// is row first+i of the source.
+func importPaths(ctx context.Context, rows []AircraftData, first int) []importPath {
+	return []importPath{
+		{"row by row", func(_ pgx.Tx, queries *db.Queries) error {
+			_, err := insertRows(ctx, queries, rows, first, &Report{})
+			return err
+		}},
+		{"bulk", func(tx pgx.Tx, _ *db.Queries) error {
+			_, err := bulkLoad(ctx, tx, rows)
+			return err
+		}},
+	}
+}
+
+// timeLoad runs a load in a transaction that is rolled back afterwards
+func timeLoad(ctx context.Context, database *database.Database, load func(pgx.Tx, *db.Queries) error) (time.Duration, error) {
+	tx, queries, err := database.BeginTx(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback(ctx)
+
+	start := time.Now()
+	if err := load(tx, queries); err != nil {
+		return 0, err
+	}
+	return time.Since(start), nil
+}
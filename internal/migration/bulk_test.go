@@ -0,0 +1,92 @@
+package migration
+
+import (
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/database"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// benchmarkFile is the workbook the import benchmark loads
+const benchmarkFile = "../../aircraft_data.xlsx"
+
+// testDatabase connects to the migrated database at TEST_DATABASE_URL,
+// skipping when it is not set
+func testDatabase(tb testing.TB) *database.Database {
+	tb.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		tb.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		tb.Fatal(err)
+	}
+	tb.Cleanup(pool.Close)
+	return &database.Database{Pool: pool, Queries: db.New(pool)}
+}
+
+// BenchmarkImport compares the row by row and the bulk import of the FAA
+// workbook. Every iteration runs in a transaction that is rolled back, and
+// only the load itself is timed.
+//
+//	TEST_DATABASE_URL=postgres://... go test -bench Import -run '^$' ./internal/migration
+func BenchmarkImport(b *testing.B) {
+	database := testDatabase(b)
+	ctx := context.Background()
+
+	src, err := OpenSource(benchmarkFile, "")
+	if err != nil {
+		b.Fatal(err)
+	}
+	rows, err := readSource(src, Options{}, &Report{})
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	for _, p := range importPaths(ctx, rows, src.firstRow()) {
+		b.Run(strings.ReplaceAll(p.name, " ", "_"), func(b *testing.B) {
+			for i := 0; i < b.N; i++ {
+				b.StopTimer()
+				tx, queries, err := database.BeginTx(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+
+				err = p.load(tx, queries)
+
+				b.StopTimer()
+				tx.Rollback(ctx)
+				if err != nil {
+					b.Fatal(err)
+				}
+				b.StartTimer()
+			}
+			b.ReportMetric(float64(len(rows)), "rows/op")
+		})
+	}
+}
+
+func TestDedupeRows(t *testing.T) {
+	rows := []AircraftData{
+		{ICAOCode: "B738", FAADesignator: "B738", Manufacturer: "first"},
+		{ICAOCode: "A320", FAADesignator: "A320"},
+		{ICAOCode: "B738", FAADesignator: "B738", Manufacturer: "last"},
+		{FAADesignator: "NOCODE"},
+		{FAADesignator: "NOCODE"},
+	}
+
+	got := dedupeRows(rows)
+	if len(got) != 4 {
+		t.Fatalf("expected 4 rows, got %d", len(got))
+	}
+	if got[0].ICAOCode != "A320" || got[1].Manufacturer != "last" {
+		t.Fatalf("expected the later B738 row to be kept in its place, got %+v", got[:2])
+	}
+}
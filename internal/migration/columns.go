@@ -0,0 +1,174 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"strings"
+	"unicode"
+)
+
+// Column maps a source column to a field of AircraftData. Header is the
+// entry of Headers naming the field; Aliases are other spellings of the
+// header used by earlier releases of the workbook. Headers match after
+// normalizing, so case, spaces and punctuation do not matter.
+type Column struct {
+	Header   string   `json:"header"`
+	Aliases  []string `json:"aliases,omitempty"`
+	Required bool     `json:"required,omitempty"`
+}
+
+// requiredHeaders are the columns an import cannot do without: the key
+// the import upserts on and the attributes classification, compatibility
+// and gate fit depend on
+var requiredHeaders = map[string]bool{
+	"ICAO_Code":                           true,
+	"FAA_Designator":                      true,
+	"Manufacturer":                        true,
+	"Model_FAA":                           true,
+	"AAC":                                 true,
+	"ADG":                                 true,
+	"TDG":                                 true,
+	"Wingspan_ft_with_winglets_sharklets": true,
+	"Length_ft":                           true,
+	"Tail_Height_at_OEW_ft":               true,
+	"MTOW_lb":                             true,
+}
+
+// headerAliases lists known historical spellings of headers. Only true
+// renames belong here: operations counts of another fiscal year are other
+// data, so TMFS_Operations_FY23 is not an alias of TMFS_Operations_FY24.
+var headerAliases = map[string][]string{
+	"LastUpdate": {"Last_Update", "Last_Updated"},
+}
+
+// DefaultColumns returns the mapping of the current FAA workbook, one
+// column per entry of Headers
+func DefaultColumns() []Column {
+	columns := make([]Column, len(Headers))
+	for i, h := range Headers {
+		columns[i] = Column{
+			Header:   h,
+			Aliases:  append([]string(nil), headerAliases[h]...),
+			Required: requiredHeaders[h],
+		}
+	}
+	return columns
+}
+
+// LoadColumns reads a JSON array of columns and applies it over the
+// default mapping. An entry replaces the aliases and required flag of the
+// column with its header; columns the file leaves out keep their defaults.
+func LoadColumns(path string) ([]Column, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read column mapping: %w", err)
+	}
+
+	var overrides []Column
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return nil, fmt.Errorf("invalid column mapping %s: %w", path, err)
+	}
+
+	columns := DefaultColumns()
+	for _, o := range overrides {
+		i := headerIndex(o.Header)
+		if i < 0 {
+			return nil, fmt.Errorf("invalid column mapping %s: unknown header %q", path, o.Header)
+		}
+		columns[i] = Column{Header: Headers[i], Aliases: o.Aliases, Required: o.Required}
+	}
+	return columns, nil
+}
+
+// headerIndex returns the position of a header in Headers, or -1
+func headerIndex(header string) int {
+	for i, h := range Headers {
+		if h == header {
+			return i
+		}
+	}
+	return -1
+}
+
+// normalizeHeader reduces a header to its lower case letters and digits
+func normalizeHeader(header string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(header) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
+
+// layout holds for every entry of Headers the index of the source column
+// holding it, or -1 when the source lacks the column
+type layout []int
+
+// resolveLayout matches the header row of a source against the columns. It
+// fails when required columns are missing and logs a warning for missing
+// optional columns and for source columns no column maps.
+func resolveLayout(header []string, columns []Column) (layout, error) {
+	if columns == nil {
+		columns = DefaultColumns()
+	}
+
+	// Index the source headers, keeping the first of repeated headers
+	positions := map[string]int{}
+	used := map[int]bool{}
+	for i, h := range header {
+		name := normalizeHeader(h)
+		if name == "" {
+			continue
+		}
+		if _, ok := positions[name]; ok {
+			log.Printf("Warning: column %q appears more than once; using the first", strings.TrimSpace(h))
+			used[i] = true
+			continue
+		}
+		positions[name] = i
+	}
+
+	l := make(layout, len(Headers))
+	for i := range l {
+		l[i] = -1
+	}
+
+	var missing []string
+	for _, c := range columns {
+		field := headerIndex(c.Header)
+		if field < 0 {
+			return nil, fmt.Errorf("column mapping names unknown header %q", c.Header)
+		}
+
+		for _, name := range append([]string{c.Header}, c.Aliases...) {
+			if pos, ok := positions[normalizeHeader(name)]; ok && !used[pos] {
+				l[field] = pos
+				used[pos] = true
+				break
+			}
+		}
+
+		if l[field] < 0 {
+			if c.Required {
+				missing = append(missing, c.Header)
+			} else {
+				log.Printf("Warning: column %s is missing; its values will be empty", c.Header)
+			}
+		}
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("missing required columns: %s", strings.Join(missing, ", "))
+	}
+
+	for i, h := range header {
+		if !used[i] && strings.TrimSpace(h) != "" {
+			log.Printf("Warning: ignoring unknown column %q", strings.TrimSpace(h))
+		}
+	}
+
+	return l, nil
+}
@@ -4,15 +4,65 @@ import (
 	"context"
 	"fmt"
 	"log"
+	"math"
+	"path/filepath"
 	"strconv"
 	"strings"
 
 	"github.com/dukerupert/faa-aircraft-search/internal/database"
 	"github.com/dukerupert/faa-aircraft-search/internal/db"
 	"github.com/jackc/pgx/v5/pgtype"
-	"github.com/xuri/excelize/v2"
 )
 
+// SheetName is the worksheet of the FAA workbook holding the aircraft data
+const SheetName = "ACD_Data"
+
+// Headers lists the column headers of the ACD_Data sheet in the order
+// parseRow reads them
+var Headers = []string{
+	"ICAO_Code",
+	"FAA_Designator",
+	"Manufacturer",
+	"Model_FAA",
+	"Model_BADA",
+	"Physical_Class_Engine",
+	"Num_Engines",
+	"AAC",
+	"AAC_minimum",
+	"AAC_maximum",
+	"ADG",
+	"TDG",
+	"Approach_Speed_knot",
+	"Approach_Speed_minimum_knot",
+	"Approach_Speed_maximum_knot",
+	"Wingspan_ft_without_winglets_sharklets",
+	"Wingspan_ft_with_winglets_sharklets",
+	"Length_ft",
+	"Tail_Height_at_OEW_ft",
+	"Wheelbase_ft",
+	"Cockpit_to_Main_Gear_ft",
+	"Main_Gear_Width_ft",
+	"MTOW_lb",
+	"MALW_lb",
+	"Main_Gear_Config",
+	"ICAO_WTC",
+	"Parking_Area_ft2",
+	"Class",
+	"FAA_Weight",
+	"CWT",
+	"One_Half_Wake_Category",
+	"Two_Wake_Category_Appx_A",
+	"Two_Wake_Category_Appx_B",
+	"Rotor_Diameter_ft",
+	"SRS",
+	"LAHSO",
+	"FAA_Registry",
+	"Registration_Count",
+	"TMFS_Operations_FY24",
+	"Remarks",
+	"LastUpdate",
+}
+
 type AircraftData struct {
 	ICAOCode                           string
 	FAADesignator                      string
@@ -57,53 +107,250 @@ type AircraftData struct {
 	LastUpdate                         string
 }
 
-// MigrateFromExcel imports aircraft data from an Excel file into the database
-func MigrateFromExcel(ctx context.Context, database *database.Database, filePath string) error {
-	log.Printf("Starting migration from Excel file: %s", filePath)
+// Options configures how a file is read and imported
+type Options struct {
+	// Columns maps the sheet headers to fields; nil uses DefaultColumns
+	Columns []Column
+	// MaxErrors is the number of problems tolerated before an import is
+	// refused. The zero value refuses any problem; NoErrorLimit tolerates
+	// any number.
+	MaxErrors int
+	// Bulk loads the rows with COPY and a single merge instead of one
+	// upsert per row
+	Bulk bool
+}
+
+// MigrateFromSource imports aircraft data from a source into the
+// database. Every row is validated first, and nothing is written when the
+// problems exceed opts.MaxErrors. The rows are then written in a single
+// transaction, so the import applies completely or not at all, and
+// aircraft the source no longer lists are deleted, so the live data
+// matches the source. The report lists the problems found and the row that
+// failed to insert, if any.
+func MigrateFromSource(ctx context.Context, database *database.Database, src *Source, opts Options) (*Report, error) {
+	log.Printf("Starting migration from %s file: %s", src.Format, src.Name)
+
+	report := &Report{File: src.Name, Problems: []Problem{}}
+	rows, err := readSource(src, opts, report)
+	if err != nil {
+		return nil, err
+	}
+
+	log.Printf("Found %d data rows with %d problems", report.Rows, report.Errors)
 
-	// Open Excel file
-	f, err := excelize.OpenFile(filePath)
+	if report.Exceeds(opts.MaxErrors) {
+		return report, fmt.Errorf("found %d errors, more than the %d allowed; nothing was imported", report.Errors, opts.MaxErrors)
+	}
+
+	// The whole import runs in one transaction, so the live data keeps
+	// serving the previous release until every row is written, and a
+	// failure leaves it untouched
+	tx, queries, err := database.BeginTx(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to open Excel file: %w", err)
+		return report, err
 	}
-	defer f.Close()
+	defer tx.Rollback(ctx)
 
-	// Get all rows from ACD_Data sheet
-	rows, err := f.GetRows("ACD_Data")
+	if opts.Bulk {
+		counts, err := bulkLoad(ctx, tx, rows)
+		if err != nil {
+			return report, fmt.Errorf("bulk load failed; nothing was imported: %w", err)
+		}
+		report.Merge = &counts
+		report.Removed = counts.Deleted
+		log.Printf("Bulk load merged %d rows: %d inserted, %d updated, %d unchanged, %d deleted",
+			counts.Inserted+counts.Updated+counts.Unchanged, counts.Inserted, counts.Updated, counts.Unchanged, counts.Deleted)
+	} else {
+		removed, err := insertRows(ctx, queries, rows, src.firstRow(), report)
+		if err != nil {
+			return report, err
+		}
+		report.Removed = removed
+		log.Printf("Deleted %d aircraft missing from the source", removed)
+	}
+
+	version, err := recordVersion(ctx, queries, src, int32(len(rows)))
 	if err != nil {
-		return fmt.Errorf("failed to get rows: %w", err)
+		return report, fmt.Errorf("failed to record dataset version: %w", err)
 	}
 
-	if len(rows) == 0 {
-		return fmt.Errorf("no rows found in Excel file")
+	if err := tx.Commit(ctx); err != nil {
+		return report, fmt.Errorf("failed to commit import: %w", err)
 	}
+	report.Imported = len(rows)
 
-	log.Printf("Found %d total rows (including header)", len(rows))
+	log.Printf("Migration completed. Imported %d rows as dataset version %d", len(rows), version.ID)
 
-	// Skip header row and process data rows
-	successCount := 0
-	errorCount := 0
-	
-	for i, row := range rows[1:] {
-		aircraft := parseRow(row)
-		
-		err := insertAircraftData(ctx, database, aircraft)
+	return report, nil
+}
+
+// insertRows upserts the rows one at a time and then deletes the aircraft
+// no row wrote, returning how many were deleted. Row i is row first+i of
+// the source.
+func insertRows(ctx context.Context, queries *db.Queries, rows []AircraftData, first int, report *Report) (int, error) {
+	ids := make([]int32, 0, len(rows))
+	for i, aircraft := range rows {
+		id, err := insertAircraftData(ctx, queries, aircraft)
 		if err != nil {
-			log.Printf("Failed to insert row %d: %v", i+2, err)
-			errorCount++
-			continue
+			report.add(first+i, Problem{Problem: ProblemInsertFailed, Message: err.Error()})
+			return 0, fmt.Errorf("failed to insert row %d; nothing was imported: %w", first+i, err)
 		}
-		
-		successCount++
-		if successCount%100 == 0 {
-			log.Printf("Successfully processed %d rows", successCount)
+		ids = append(ids, id)
+
+		if (i+1)%100 == 0 {
+			log.Printf("Successfully processed %d rows", i+1)
 		}
 	}
 
-	log.Printf("Migration completed. Success: %d, Errors: %d, Total: %d", 
-		successCount, errorCount, len(rows)-1)
+	removed, err := queries.DeleteAircraftDataExcept(ctx, ids)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete aircraft missing from the source; nothing was imported: %w", err)
+	}
+	return int(removed), nil
+}
 
-	return nil
+// Rollback undoes the latest import. It restores aircraft_data from the
+// snapshot of the previous dataset version and marks the latest version as
+// rolled back, keeping its record and snapshot. Rolled back versions are
+// skipped, so repeated rollbacks step further back. It returns the
+// restored version.
+func Rollback(ctx context.Context, database *database.Database) (db.DatasetVersion, error) {
+	tx, queries, err := database.BeginTx(ctx)
+	if err != nil {
+		return db.DatasetVersion{}, err
+	}
+	defer tx.Rollback(ctx)
+
+	versions, err := queries.ListDatasetVersions(ctx)
+	if err != nil {
+		return db.DatasetVersion{}, fmt.Errorf("failed to list dataset versions: %w", err)
+	}
+	var live []db.DatasetVersion
+	for _, v := range versions {
+		if !v.RolledBackAt.Valid {
+			live = append(live, v)
+		}
+	}
+	if len(live) < 2 {
+		return db.DatasetVersion{}, fmt.Errorf("no previous dataset to restore: %d dataset versions not rolled back", len(live))
+	}
+	latest, previous := live[0], live[1]
+
+	if err := queries.DeleteAllAircraftData(ctx); err != nil {
+		return db.DatasetVersion{}, fmt.Errorf("failed to clear aircraft data: %w", err)
+	}
+
+	restored, err := queries.RestoreAircraftData(ctx, previous.ID)
+	if err != nil {
+		return db.DatasetVersion{}, fmt.Errorf("failed to restore dataset version %d: %w", previous.ID, err)
+	}
+
+	if err := queries.MarkDatasetVersionRolledBack(ctx, latest.ID); err != nil {
+		return db.DatasetVersion{}, fmt.Errorf("failed to mark dataset version %d as rolled back: %w", latest.ID, err)
+	}
+
+	if err := tx.Commit(ctx); err != nil {
+		return db.DatasetVersion{}, fmt.Errorf("failed to commit rollback: %w", err)
+	}
+
+	log.Printf("Rolled back dataset version %d and restored %d rows of version %d", latest.ID, restored, previous.ID)
+	return previous, nil
+}
+
+// recordVersion creates a dataset version for an import and snapshots the
+// aircraft data as it stands after the import into the history table. It
+// runs in the transaction of the import.
+func recordVersion(ctx context.Context, queries *db.Queries, src *Source, rowCount int32) (db.DatasetVersion, error) {
+	version, err := queries.CreateDatasetVersion(ctx, db.CreateDatasetVersionParams{
+		SourceFile:   filepath.Base(src.Name),
+		SourceSha256: pgtype.Text{String: src.SHA256(), Valid: true},
+		SheetName:    src.Sheet(),
+		RowCount:     rowCount,
+	})
+	if err != nil {
+		return db.DatasetVersion{}, err
+	}
+
+	if _, err := queries.SnapshotAircraftData(ctx, version.ID); err != nil {
+		return db.DatasetVersion{}, err
+	}
+
+	return version, nil
+}
+
+// ReadAll parses the data rows of a source
+func ReadAll(src *Source, opts Options) ([]AircraftData, error) {
+	// Problems are of no interest here; unparsable cells read as empty
+	return readSource(src, opts, &Report{})
+}
+
+// Record returns the values of the row in the column order of Headers,
+// typed like export.Record so that rows read from a file compare with rows
+// read from the database. Decimals are rounded to the two places the
+// database keeps.
+func (a AircraftData) Record() []any {
+	text := func(s string) any {
+		if s == "" {
+			return nil
+		}
+		return s
+	}
+	integer := func(i *int32) any {
+		if i == nil {
+			return nil
+		}
+		return *i
+	}
+	decimal := func(f *float64) any {
+		if f == nil {
+			return nil
+		}
+		return math.Round(*f*100) / 100
+	}
+
+	return []any{
+		text(a.ICAOCode),
+		text(a.FAADesignator),
+		text(a.Manufacturer),
+		text(a.ModelFAA),
+		text(a.ModelBADA),
+		text(a.PhysicalClassEngine),
+		integer(a.NumEngines),
+		text(a.AAC),
+		text(a.AACMinimum),
+		text(a.AACMaximum),
+		text(a.ADG),
+		text(a.TDG),
+		integer(a.ApproachSpeedKnot),
+		integer(a.ApproachSpeedMinimumKnot),
+		integer(a.ApproachSpeedMaximumKnot),
+		decimal(a.WingspanFtWithoutWingletsSharklets),
+		decimal(a.WingspanFtWithWingletsSharklets),
+		decimal(a.LengthFt),
+		decimal(a.TailHeightAtOEWFt),
+		decimal(a.WheelbaseFt),
+		decimal(a.CockpitToMainGearFt),
+		decimal(a.MainGearWidthFt),
+		integer(a.MTOWLB),
+		integer(a.MALWLB),
+		text(a.MainGearConfig),
+		text(a.ICAOWTC),
+		decimal(a.ParkingAreaFt2),
+		text(a.Class),
+		text(a.FAAWeight),
+		text(a.CWT),
+		text(a.OneHalfWakeCategory),
+		text(a.TwoWakeCategoryAppxA),
+		text(a.TwoWakeCategoryAppxB),
+		decimal(a.RotorDiameterFt),
+		text(a.SRS),
+		text(a.LAHSO),
+		text(a.FAARegistry),
+		integer(a.RegistrationCount),
+		integer(a.TMFSOperationsFY24),
+		text(a.Remarks),
+		text(a.LastUpdate),
+	}
 }
 
 // ClearData removes all aircraft data from the database
@@ -125,41 +372,55 @@ func GetRecordCount(ctx context.Context, database *database.Database) (int64, er
 	return count, err
 }
 
-func parseRow(row []string) AircraftData {
+// parseRow reads a data row. Fields are numbered in the order of Headers
+// and looked up in the sheet through the layout of its header row. Numbers
+// that do not parse or lie outside their plausible range are reported as
+// problems; unparsable numbers are read as empty.
+func parseRow(row []string, l layout) (AircraftData, []Problem) {
+	var problems []Problem
+
 	// Helper function to safely get string value
-	getString := func(index int) string {
-		if index < len(row) {
+	getString := func(field int) string {
+		if index := l[field]; index >= 0 && index < len(row) {
 			return strings.TrimSpace(row[index])
 		}
 		return ""
 	}
 
 	// Helper function to safely parse int32
-	getInt32 := func(index int) *int32 {
-		if index < len(row) && strings.TrimSpace(row[index]) != "" && strings.TrimSpace(row[index]) != "N/A" {
+	getInt32 := func(field int) *int32 {
+		if value := getString(field); value != "" && value != "N/A" {
 			// Remove commas for numbers like "50,045"
-			cleanStr := strings.ReplaceAll(strings.TrimSpace(row[index]), ",", "")
-			if val, err := strconv.ParseInt(cleanStr, 10, 32); err == nil {
-				val32 := int32(val)
-				return &val32
+			cleanStr := strings.ReplaceAll(value, ",", "")
+			val, err := strconv.ParseInt(cleanStr, 10, 32)
+			if err != nil {
+				problems = append(problems, unparsable(field, value, "a whole number"))
+				return nil
 			}
+			problems = append(problems, checkRange(field, value, float64(val))...)
+			val32 := int32(val)
+			return &val32
 		}
 		return nil
 	}
 
 	// Helper function to safely parse float
-	getFloat := func(index int) *float64 {
-		if index < len(row) && strings.TrimSpace(row[index]) != "" && strings.TrimSpace(row[index]) != "N/A" {
+	getFloat := func(field int) *float64 {
+		if value := getString(field); value != "" && value != "N/A" {
 			// Remove commas for numbers
-			cleanStr := strings.ReplaceAll(strings.TrimSpace(row[index]), ",", "")
-			if val, err := strconv.ParseFloat(cleanStr, 64); err == nil {
-				return &val
+			cleanStr := strings.ReplaceAll(value, ",", "")
+			val, err := strconv.ParseFloat(cleanStr, 64)
+			if err != nil {
+				problems = append(problems, unparsable(field, value, "a number"))
+				return nil
 			}
+			problems = append(problems, checkRange(field, value, val)...)
+			return &val
 		}
 		return nil
 	}
 
-	return AircraftData{
+	aircraft := AircraftData{
 		ICAOCode:                           getString(0),
 		FAADesignator:                      getString(1),
 		Manufacturer:                       getString(2),
@@ -202,9 +463,16 @@ func parseRow(row []string) AircraftData {
 		Remarks:                            getString(39),
 		LastUpdate:                         getString(40),
 	}
+	return aircraft, problems
+}
+
+func insertAircraftData(ctx context.Context, queries *db.Queries, aircraft AircraftData) (int32, error) {
+	row, err := queries.UpsertAircraftData(ctx, upsertParams(aircraft))
+	return row.ID, err
 }
 
-func insertAircraftData(ctx context.Context, database *database.Database, aircraft AircraftData) error {
+// upsertParams converts a parsed row to the parameters of the upsert
+func upsertParams(aircraft AircraftData) db.UpsertAircraftDataParams {
 	// Helper function to convert string to pgtype.Text
 	stringToPgText := func(s string) pgtype.Text {
 		if s == "" {
@@ -235,7 +503,7 @@ func insertAircraftData(ctx context.Context, database *database.Database, aircra
 	}
 
 	// Convert our internal struct to SQLC parameters
-	params := db.UpsertAircraftDataParams{
+	return db.UpsertAircraftDataParams{
 		IcaoCode:                         stringToPgText(aircraft.ICAOCode),
 		FaaDesignator:                    stringToPgText(aircraft.FAADesignator),
 		Manufacturer:                     stringToPgText(aircraft.Manufacturer),
@@ -278,8 +546,4 @@ func insertAircraftData(ctx context.Context, database *database.Database, aircra
 		Remarks:                            stringToPgText(aircraft.Remarks),
 		LastUpdate:                         stringToPgText(aircraft.LastUpdate),
 	}
-
-	// Use SQLC-generated upsert function
-	_, err := database.Queries.UpsertAircraftData(ctx, params)
-	return err
 }
\ No newline at end of file
@@ -0,0 +1,307 @@
+package migration
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/csv"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/xuri/excelize/v2"
+)
+
+// Format is the file format of an import source
+type Format string
+
+const (
+	FormatXLSX   Format = "xlsx"
+	FormatCSV    Format = "csv"
+	FormatJSON   Format = "json"
+	FormatNDJSON Format = "ndjson"
+)
+
+// ParseFormat validates an import format name
+func ParseFormat(name string) (Format, error) {
+	switch f := Format(name); f {
+	case FormatXLSX, FormatCSV, FormatJSON, FormatNDJSON:
+		return f, nil
+	default:
+		return "", fmt.Errorf("unsupported import format %q: must be xlsx, csv, json or ndjson", name)
+	}
+}
+
+// formatExtensions maps file extensions to the format they imply
+var formatExtensions = map[string]Format{
+	".xlsx":   FormatXLSX,
+	".csv":    FormatCSV,
+	".json":   FormatJSON,
+	".ndjson": FormatNDJSON,
+	".jsonl":  FormatNDJSON,
+}
+
+// StdinPath is the file path that reads a source from standard input
+const StdinPath = "-"
+
+// Source is a file of aircraft rows to import. Every format is read into
+// the same header and data rows, so all of them share the column mapping,
+// validation and upsert of the Excel import.
+//
+// An Excel source is the ACD_Data sheet of a workbook and a CSV source a
+// header row followed by data rows. A JSON source is an array of objects
+// and an NDJSON source one object per line; object keys are headers and
+// values are strings, numbers or null.
+type Source struct {
+	// Name is the path the source was read from, or "stdin"
+	Name   string
+	Format Format
+	data   []byte
+}
+
+// OpenSource reads the file at path, or standard input when path is
+// StdinPath. An empty format is taken from the file extension, or guessed
+// from the content when the extension is unknown.
+func OpenSource(path string, format Format) (*Source, error) {
+	if path == StdinPath {
+		return ReadSource(os.Stdin, "stdin", format)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open file: %w", err)
+	}
+	defer f.Close()
+
+	return ReadSource(f, path, format)
+}
+
+// ReadSource reads a source from r. The whole source is held in memory,
+// since a workbook cannot be read as a stream and the dataset version
+// records a digest of the content.
+func ReadSource(r io.Reader, name string, format Format) (*Source, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", name, err)
+	}
+
+	if format == "" {
+		format = detectFormat(name, data)
+	}
+	return &Source{Name: name, Format: format, data: data}, nil
+}
+
+// detectFormat picks the format of a source from the extension of its
+// name, and otherwise from its first bytes: a zip archive is a workbook,
+// a leading bracket a JSON array and a leading brace NDJSON. Anything else
+// is read as CSV.
+func detectFormat(name string, data []byte) Format {
+	if f, ok := formatExtensions[strings.ToLower(filepath.Ext(name))]; ok {
+		return f
+	}
+
+	if bytes.HasPrefix(data, []byte("PK\x03\x04")) {
+		return FormatXLSX
+	}
+	switch text := bytes.TrimLeft(trimBOM(data), " \t\r\n"); {
+	case bytes.HasPrefix(text, []byte("[")):
+		return FormatJSON
+	case bytes.HasPrefix(text, []byte("{")):
+		return FormatNDJSON
+	default:
+		return FormatCSV
+	}
+}
+
+// SHA256 returns the hex SHA-256 digest of the content
+func (s *Source) SHA256() string {
+	sum := sha256.Sum256(s.data)
+	return hex.EncodeToString(sum[:])
+}
+
+// Sheet returns the worksheet the rows come from; only workbooks have one
+func (s *Source) Sheet() string {
+	if s.Format == FormatXLSX {
+		return SheetName
+	}
+	return ""
+}
+
+// firstRow is the row number of the first data row. Spreadsheets and CSV
+// count the header as row 1; JSON sources number their objects from 1.
+func (s *Source) firstRow() int {
+	if s.Format == FormatJSON || s.Format == FormatNDJSON {
+		return 1
+	}
+	return 2
+}
+
+// table returns the header and data rows of the source
+func (s *Source) table() ([]string, [][]string, error) {
+	switch s.Format {
+	case FormatXLSX:
+		return readWorkbook(s.data)
+	case FormatCSV:
+		return readCSV(s.data)
+	case FormatJSON:
+		return readJSON(s.data, false)
+	case FormatNDJSON:
+		return readJSON(s.data, true)
+	default:
+		return nil, nil, fmt.Errorf("unsupported import format %q", s.Format)
+	}
+}
+
+// readWorkbook reads the rows of the ACD_Data sheet
+func readWorkbook(data []byte) ([]string, [][]string, error) {
+	f, err := excelize.OpenReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open Excel file: %w", err)
+	}
+	defer f.Close()
+
+	rows, err := f.GetRows(SheetName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get rows: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("no rows found in Excel file")
+	}
+	return rows[0], rows[1:], nil
+}
+
+// readCSV reads a header row and comma separated data rows. Rows may have
+// fewer cells than the header, like sheet rows with empty trailing cells.
+func readCSV(data []byte) ([]string, [][]string, error) {
+	r := csv.NewReader(bytes.NewReader(trimBOM(data)))
+	r.FieldsPerRecord = -1
+
+	rows, err := r.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("no rows found in CSV file")
+	}
+	return rows[0], rows[1:], nil
+}
+
+// readJSON reads a JSON array of objects, or a stream of objects when
+// ndjson is set. The header lists the keys in the order they first appear.
+func readJSON(data []byte, ndjson bool) ([]string, [][]string, error) {
+	dec := json.NewDecoder(bytes.NewReader(trimBOM(data)))
+	dec.UseNumber()
+
+	if !ndjson {
+		if err := expectDelim(dec, '['); err != nil {
+			return nil, nil, fmt.Errorf("failed to read JSON: %w", err)
+		}
+	}
+
+	var (
+		header  []string
+		columns = map[string]int{}
+		rows    [][]string
+	)
+	for dec.More() {
+		object, err := readObject(dec)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read JSON object %d: %w", len(rows)+1, err)
+		}
+
+		row := make([]string, len(header))
+		for _, field := range object {
+			i, ok := columns[field.key]
+			if !ok {
+				i = len(header)
+				columns[field.key] = i
+				header = append(header, field.key)
+			}
+			for len(row) <= i {
+				row = append(row, "")
+			}
+			row[i] = field.value
+		}
+		rows = append(rows, row)
+	}
+
+	if !ndjson {
+		if err := expectDelim(dec, ']'); err != nil {
+			return nil, nil, fmt.Errorf("failed to read JSON: %w", err)
+		}
+	}
+
+	if len(rows) == 0 {
+		return nil, nil, fmt.Errorf("no objects found in JSON")
+	}
+	return header, rows, nil
+}
+
+// jsonField is a key of a JSON object with its value as cell text
+type jsonField struct {
+	key, value string
+}
+
+// readObject reads the next JSON object, keeping its keys in order
+func readObject(dec *json.Decoder) ([]jsonField, error) {
+	if err := expectDelim(dec, '{'); err != nil {
+		return nil, err
+	}
+
+	var fields []jsonField
+	for dec.More() {
+		token, err := dec.Token()
+		if err != nil {
+			return nil, err
+		}
+		key := token.(string)
+
+		var value any
+		if err := dec.Decode(&value); err != nil {
+			return nil, err
+		}
+
+		var text string
+		switch v := value.(type) {
+		case nil:
+		case string:
+			text = v
+		case json.Number:
+			text = v.String()
+		case bool:
+			text = fmt.Sprint(v)
+		default:
+			return nil, fmt.Errorf("value of %q must be a string, number or null", key)
+		}
+		fields = append(fields, jsonField{key, text})
+	}
+
+	return fields, expectDelim(dec, '}')
+}
+
+// expectDelim reads the next token and fails unless it is the delimiter
+func expectDelim(dec *json.Decoder, delim json.Delim) error {
+	token, err := dec.Token()
+	if errors.Is(err, io.EOF) {
+		return fmt.Errorf("expected %v, found end of input", delim)
+	}
+	if err != nil {
+		return err
+	}
+	if token != delim {
+		return fmt.Errorf("expected %v, found %v", delim, token)
+	}
+	return nil
+}
+
+// trimBOM drops the UTF-8 byte order mark spreadsheet programs put in
+// front of CSV and text files
+func trimBOM(data []byte) []byte {
+	return bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+}
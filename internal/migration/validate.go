@@ -0,0 +1,188 @@
+package migration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Kinds of problems found while validating a sheet
+const (
+	ProblemUnparsableNumber = "unparsable_number"
+	ProblemOutOfRange       = "out_of_range"
+	ProblemDuplicateKey     = "duplicate_key"
+	ProblemMissingCode      = "missing_code"
+	ProblemInsertFailed     = "insert_failed"
+)
+
+// NoErrorLimit disables the error threshold of Options.MaxErrors
+const NoErrorLimit = -1
+
+// Problem is a defect of a row. Row is the row number in the sheet or CSV
+// file, where the header is row 1, or the position of the object in a JSON
+// source, counting from 1. Column and Value name the offending cell and its
+// raw content, and are empty for problems of the whole row.
+type Problem struct {
+	Row     int    `json:"row"`
+	Column  string `json:"column,omitempty"`
+	Value   string `json:"value,omitempty"`
+	Problem string `json:"problem"`
+	Message string `json:"message"`
+}
+
+// Report is the outcome of validating or importing a file. Every problem
+// counts as an error. Unparsable numbers are imported as empty values,
+// while out of range values and rows with problems are imported as they
+// are. Removed counts the aircraft an import deleted because the file no
+// longer lists them. Merge is set by bulk imports.
+type Report struct {
+	File     string       `json:"file"`
+	DryRun   bool         `json:"dry_run"`
+	Rows     int          `json:"rows"`
+	Imported int          `json:"imported"`
+	Removed  int          `json:"removed"`
+	Merge    *MergeCounts `json:"merge,omitempty"`
+	Errors   int          `json:"errors"`
+	Problems []Problem    `json:"problems"`
+}
+
+// add records problems of a sheet row
+func (r *Report) add(row int, problems ...Problem) {
+	for _, p := range problems {
+		p.Row = row
+		r.Problems = append(r.Problems, p)
+	}
+	r.Errors = len(r.Problems)
+}
+
+// Exceeds reports whether the report has more errors than max allows.
+// A negative max allows any number.
+func (r *Report) Exceeds(max int) bool {
+	return max >= 0 && r.Errors > max
+}
+
+// WriteFile writes the report as indented JSON
+func (r *Report) WriteFile(path string) error {
+	data, err := json.MarshalIndent(r, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0o644)
+}
+
+// valueRange bounds the plausible values of a numeric column
+type valueRange struct {
+	min, max float64
+}
+
+// valueRanges holds the plausible range of every numeric column. They are
+// generous; values outside them are typos or unit mix-ups.
+var valueRanges = map[string]valueRange{
+	"Num_Engines":                            {0, 8},
+	"Approach_Speed_knot":                    {0, 250},
+	"Approach_Speed_minimum_knot":            {0, 250},
+	"Approach_Speed_maximum_knot":            {0, 250},
+	"Wingspan_ft_without_winglets_sharklets": {0, 400},
+	"Wingspan_ft_with_winglets_sharklets":    {0, 400},
+	"Length_ft":                              {0, 400},
+	"Tail_Height_at_OEW_ft":                  {0, 150},
+	"Wheelbase_ft":                           {0, 200},
+	"Cockpit_to_Main_Gear_ft":                {0, 250},
+	"Main_Gear_Width_ft":                     {0, 100},
+	"MTOW_lb":                                {0, 1500000},
+	"MALW_lb":                                {0, 1500000},
+	"Parking_Area_ft2":                       {0, 200000},
+	"Rotor_Diameter_ft":                      {0, 150},
+	"Registration_Count":                     {0, 1000000},
+	"TMFS_Operations_FY24":                   {0, 100000000},
+}
+
+// checkRange returns an out of range problem when a value of a column
+// lies outside its plausible range
+func checkRange(field int, raw string, value float64) []Problem {
+	header := Headers[field]
+	bounds, ok := valueRanges[header]
+	if !ok || (value >= bounds.min && value <= bounds.max) {
+		return nil
+	}
+	return []Problem{{
+		Column:  header,
+		Value:   raw,
+		Problem: ProblemOutOfRange,
+		Message: fmt.Sprintf("%s must be between %s and %s", header, formatBound(bounds.min), formatBound(bounds.max)),
+	}}
+}
+
+// unparsable returns the problem of a cell that is not the expected number
+func unparsable(field int, raw, expected string) Problem {
+	return Problem{
+		Column:  Headers[field],
+		Value:   raw,
+		Problem: ProblemUnparsableNumber,
+		Message: fmt.Sprintf("%s must be %s", Headers[field], expected),
+	}
+}
+
+func formatBound(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// readSource parses and validates the data rows of a source. Every data
+// row yields an aircraft, so aircraft[i] is row src.firstRow()+i.
+func readSource(src *Source, opts Options, report *Report) ([]AircraftData, error) {
+	header, rows, err := src.table()
+	if err != nil {
+		return nil, err
+	}
+
+	l, err := resolveLayout(header, opts.Columns)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct{ icao, faa string }
+	seen := map[key]int{}
+
+	aircraft := make([]AircraftData, 0, len(rows))
+	for i, row := range rows {
+		rowNum := src.firstRow() + i
+		a, problems := parseRow(row, l)
+		report.add(rowNum, problems...)
+
+		if a.ICAOCode == "" && a.FAADesignator == "" {
+			report.add(rowNum, Problem{
+				Problem: ProblemMissingCode,
+				Message: "Row has neither an ICAO code nor an FAA designator",
+			})
+		} else {
+			k := key{a.ICAOCode, a.FAADesignator}
+			if first, ok := seen[k]; ok {
+				report.add(rowNum, Problem{
+					Value:   a.ICAOCode + " / " + a.FAADesignator,
+					Problem: ProblemDuplicateKey,
+					Message: fmt.Sprintf("Same ICAO code and FAA designator as row %d; the later row wins", first),
+				})
+			} else {
+				seen[k] = rowNum
+			}
+		}
+
+		aircraft = append(aircraft, a)
+	}
+
+	report.Rows = len(aircraft)
+	return aircraft, nil
+}
+
+// Validate parses and validates a source without writing to the database
+func Validate(src *Source, opts Options) (*Report, error) {
+	report := &Report{File: src.Name, DryRun: true, Problems: []Problem{}}
+	if _, err := readSource(src, opts, report); err != nil {
+		return nil, err
+	}
+	if report.Exceeds(opts.MaxErrors) {
+		return report, fmt.Errorf("found %d errors, more than the %d allowed", report.Errors, opts.MaxErrors)
+	}
+	return report, nil
+}
@@ -0,0 +1,268 @@
+// Renders the OpenAPI document of the API as an interactive reference.
+// Kept dependency free so the page works without any CDN.
+(function () {
+	"use strict";
+
+	const specURL = "/api/openapi.json";
+
+	function el(tag, attrs, ...children) {
+		const node = document.createElement(tag);
+		for (const [key, value] of Object.entries(attrs || {})) {
+			if (key === "class") {
+				node.className = value;
+			} else {
+				node.setAttribute(key, value);
+			}
+		}
+		for (const child of children) {
+			if (child !== null && child !== undefined) {
+				node.append(child);
+			}
+		}
+		return node;
+	}
+
+	function refName(ref) {
+		return ref.substring(ref.lastIndexOf("/") + 1);
+	}
+
+	// typeLabel describes a schema in a line, linking referenced components
+	function typeLabel(schema) {
+		if (!schema) {
+			return el("span", { class: "type" }, "any");
+		}
+		if (schema.$ref) {
+			const name = refName(schema.$ref);
+			return el("a", { href: "#schema-" + name, class: "type" }, name);
+		}
+		if (schema.type === "array") {
+			return el("span", {}, typeLabel(schema.items), "[]");
+		}
+		if (schema.additionalProperties) {
+			return el("span", {}, "map of ", typeLabel(schema.additionalProperties));
+		}
+		const types = [].concat(schema.type || "any");
+		let label = types.join(" | ");
+		if (schema.format) {
+			label += " (" + schema.format + ")";
+		}
+		if (schema.enum) {
+			label += ": " + schema.enum.join(", ");
+		}
+		return el("span", { class: "type" }, label);
+	}
+
+	// example builds a request body skeleton from a schema
+	function example(spec, schema, depth) {
+		if (!schema || depth > 4) {
+			return null;
+		}
+		if (schema.$ref) {
+			return example(spec, spec.components.schemas[refName(schema.$ref)], depth + 1);
+		}
+		if (schema.default !== undefined) {
+			return schema.default;
+		}
+		const type = [].concat(schema.type)[0];
+		switch (type) {
+			case "object": {
+				const out = {};
+				for (const [name, prop] of Object.entries(schema.properties || {})) {
+					out[name] = example(spec, prop, depth + 1);
+				}
+				return out;
+			}
+			case "array":
+				return [example(spec, schema.items, depth + 1)];
+			case "string":
+				return schema.enum ? schema.enum[0] : "";
+			case "integer":
+			case "number":
+				return 0;
+			case "boolean":
+				return false;
+			default:
+				return null;
+		}
+	}
+
+	function renderOperation(spec, path, method, op) {
+		const node = document.getElementById("operation").content.firstElementChild.cloneNode(true);
+		node.id = op.operationId;
+
+		const badge = node.querySelector(".method");
+		badge.textContent = method.toUpperCase();
+		badge.classList.add(method);
+		node.querySelector(".path").textContent = path;
+		node.querySelector(".summary").textContent = op.summary;
+		node.querySelector(".description").textContent = op.description || "";
+
+		const rows = node.querySelector(".parameters tbody");
+		for (const param of op.parameters || []) {
+			const name = el("td", { class: param.required ? "required" : "" }, el("code", {}, param.name));
+			const input = el("input", { name: param.name, "data-in": param.in });
+			if (param.required) {
+				input.required = true;
+			}
+			if (param.schema && param.schema.default !== undefined) {
+				input.placeholder = String(param.schema.default);
+			}
+			const description = el("td", {}, param.description || "", " ", typeLabel(param.schema));
+			rows.append(el("tr", {}, name, el("td", {}, param.in), description, el("td", {}, input)));
+		}
+		if (!rows.children.length) {
+			node.querySelector(".parameters").hidden = true;
+		}
+
+		const bodyLabel = node.querySelector(".request-body");
+		const textarea = bodyLabel.querySelector("textarea");
+		const json = op.requestBody && op.requestBody.content["application/json"];
+		if (json) {
+			bodyLabel.querySelector("span").textContent = "JSON body" + (op.requestBody.description ? ": " + op.requestBody.description : "");
+			textarea.value = JSON.stringify(example(spec, json.schema, 0), null, 2);
+		} else {
+			bodyLabel.hidden = true;
+		}
+
+		const responses = node.querySelector(".responses");
+		const list = el("ul");
+		for (const [status, response] of Object.entries(op.responses)) {
+			const item = el("li", {}, el("strong", {}, status), " " + response.description);
+			for (const [type, media] of Object.entries(response.content || {})) {
+				item.append(" (", el("code", {}, type), media.schema ? ", " : "", media.schema ? typeLabel(media.schema) : null, ")");
+			}
+			list.append(item);
+		}
+		responses.append(el("strong", {}, "Responses"), list);
+
+		node.querySelector("form").addEventListener("submit", (event) => {
+			event.preventDefault();
+			send(node, path, method, json ? textarea.value : null);
+		});
+
+		return node;
+	}
+
+	async function send(node, path, method, body) {
+		const query = new URLSearchParams();
+		let url = path;
+		for (const input of node.querySelectorAll("input[data-in]")) {
+			if (input.dataset.in === "path") {
+				url = url.replace("{" + input.name + "}", encodeURIComponent(input.value));
+			} else if (input.value !== "") {
+				query.append(input.name, input.value);
+			}
+		}
+		if (query.toString()) {
+			url += "?" + query.toString();
+		}
+
+		const result = node.querySelector(".result");
+		const status = result.querySelector(".status");
+		const output = result.querySelector("pre");
+		result.hidden = false;
+		status.className = "status";
+		status.textContent = method.toUpperCase() + " " + url;
+		output.textContent = "";
+
+		const init = { method: method.toUpperCase(), headers: {} };
+		if (body !== null) {
+			init.headers["Content-Type"] = "application/json";
+			init.body = body;
+		}
+
+		try {
+			const response = await fetch(url, init);
+			status.textContent += " → " + response.status + " " + response.statusText;
+			if (!response.ok) {
+				status.classList.add("error");
+			}
+			const type = response.headers.get("Content-Type") || "";
+			if (type.includes("json")) {
+				output.textContent = JSON.stringify(await response.json(), null, 2);
+			} else if (type.startsWith("text/")) {
+				output.textContent = await response.text();
+			} else {
+				const blob = await response.blob();
+				output.textContent = "Binary response of " + blob.size + " bytes (" + type + ")";
+			}
+		} catch (err) {
+			status.classList.add("error");
+			output.textContent = String(err);
+		}
+	}
+
+	function renderSchemas(spec) {
+		const section = el("section", { id: "schemas" }, el("h2", {}, "Schemas"));
+		for (const name of Object.keys(spec.components.schemas).sort()) {
+			const schema = spec.components.schemas[name];
+			const box = el("div", { class: "schema", id: "schema-" + name }, el("h4", {}, name));
+			if (schema.description) {
+				box.append(el("p", {}, schema.description));
+			}
+			const required = new Set(schema.required || []);
+			const rows = el("tbody");
+			for (const [prop, propSchema] of Object.entries(schema.properties || {})) {
+				rows.append(el("tr", {},
+					el("td", { class: required.has(prop) ? "required" : "" }, el("code", {}, prop)),
+					el("td", {}, typeLabel(propSchema)),
+					el("td", {}, propSchema.description || "")));
+			}
+			if (rows.children.length) {
+				box.append(el("table", {}, rows));
+			} else {
+				box.append(el("p", {}, typeLabel(schema)));
+			}
+			section.append(box);
+		}
+		return section;
+	}
+
+	async function render() {
+		const spec = await (await fetch(specURL)).json();
+		document.getElementById("description").textContent = spec.info.description || spec.info.title;
+
+		// Group operations by their first tag, in the order of the document tags
+		const groups = new Map((spec.tags || []).map((tag) => [tag.name, { tag, operations: [] }]));
+		for (const path of Object.keys(spec.paths).sort()) {
+			for (const [method, op] of Object.entries(spec.paths[path])) {
+				const name = (op.tags || ["Other"])[0];
+				if (!groups.has(name)) {
+					groups.set(name, { tag: { name }, operations: [] });
+				}
+				groups.get(name).operations.push({ path, method, op });
+			}
+		}
+
+		const toc = document.getElementById("toc");
+		const main = document.getElementById("operations");
+		for (const { tag, operations } of groups.values()) {
+			if (!operations.length) {
+				continue;
+			}
+			toc.append(el("h3", {}, tag.name));
+			main.append(el("h2", { id: "tag-" + tag.name }, tag.name));
+			if (tag.description) {
+				main.append(el("p", {}, tag.description));
+			}
+			for (const { path, method, op } of operations) {
+				toc.append(el("a", { href: "#" + op.operationId }, method.toUpperCase() + " " + path));
+				main.append(renderOperation(spec, path, method, op));
+			}
+		}
+		toc.append(el("h3", {}, el("a", { href: "#schemas" }, "Schemas")));
+		main.append(renderSchemas(spec));
+
+		if (location.hash) {
+			const target = document.querySelector(location.hash);
+			if (target && target.tagName === "DETAILS") {
+				target.open = true;
+				target.scrollIntoView();
+			}
+		}
+	}
+
+	render().catch((err) => {
+		document.getElementById("description").textContent = "Failed to load " + specURL + ": " + err;
+	});
+})();
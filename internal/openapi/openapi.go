@@ -0,0 +1,186 @@
+// Package openapi builds the OpenAPI 3.1 description of the HTTP API and
+// serves the embedded documentation page that renders it.
+package openapi
+
+import (
+	"embed"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Version is the OpenAPI version of the documents built by this package
+const Version = "3.1.0"
+
+// Docs holds the self-hosted documentation page and its assets
+//
+//go:embed docs
+var Docs embed.FS
+
+// Document is an OpenAPI document. Only the parts used by this API are
+// modelled.
+type Document struct {
+	OpenAPI    string              `json:"openapi"`
+	Info       Info                `json:"info"`
+	Tags       []Tag               `json:"tags,omitempty"`
+	Paths      map[string]PathItem `json:"paths"`
+	Components Components          `json:"components"`
+	types      map[reflect.Type]string
+}
+
+// Info describes the API
+type Info struct {
+	Title       string `json:"title"`
+	Version     string `json:"version"`
+	Description string `json:"description,omitempty"`
+}
+
+// Tag groups operations in the documentation
+type Tag struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+}
+
+// PathItem maps lower case HTTP methods to the operations of a path
+type PathItem map[string]*Operation
+
+// Operation is a single method on a path
+type Operation struct {
+	OperationID string              `json:"operationId"`
+	Tags        []string            `json:"tags,omitempty"`
+	Summary     string              `json:"summary"`
+	Description string              `json:"description,omitempty"`
+	Parameters  []Parameter         `json:"parameters,omitempty"`
+	RequestBody *RequestBody        `json:"requestBody,omitempty"`
+	Responses   map[string]Response `json:"responses"`
+}
+
+// Parameter is a path or query parameter
+type Parameter struct {
+	Name        string  `json:"name"`
+	In          string  `json:"in"`
+	Description string  `json:"description,omitempty"`
+	Required    bool    `json:"required,omitempty"`
+	Schema      *Schema `json:"schema"`
+}
+
+// RequestBody describes the accepted request bodies by media type
+type RequestBody struct {
+	Description string               `json:"description,omitempty"`
+	Required    bool                 `json:"required,omitempty"`
+	Content     map[string]MediaType `json:"content"`
+}
+
+// Response describes a response and its bodies by media type
+type Response struct {
+	Description string               `json:"description"`
+	Content     map[string]MediaType `json:"content,omitempty"`
+}
+
+// MediaType holds the schema of a body
+type MediaType struct {
+	Schema *Schema `json:"schema,omitempty"`
+}
+
+// Components holds the named schemas referenced by operations
+type Components struct {
+	Schemas map[string]*Schema `json:"schemas"`
+}
+
+// New returns an empty document
+func New(info Info, tags ...Tag) *Document {
+	return &Document{
+		OpenAPI:    Version,
+		Info:       info,
+		Tags:       tags,
+		Paths:      map[string]PathItem{},
+		Components: Components{Schemas: map[string]*Schema{}},
+		types:      map[reflect.Type]string{},
+	}
+}
+
+// Add registers an operation. The path uses Echo syntax, so :id becomes
+// {id} and the * wildcard becomes {path}.
+func (d *Document) Add(method, path string, op *Operation) {
+	path = specPath(path)
+	item, ok := d.Paths[path]
+	if !ok {
+		item = PathItem{}
+		d.Paths[path] = item
+	}
+	item[strings.ToLower(method)] = op
+}
+
+// Check compares the routes of an Echo instance with the document and
+// reports routes that are not described and operations that no longer
+// exist
+func (d *Document) Check(routes []*echo.Route) error {
+	registered := map[string]bool{}
+	var missing []string
+	for _, r := range routes {
+		key := r.Method + " " + specPath(r.Path)
+		if registered[key] {
+			continue
+		}
+		registered[key] = true
+		if item, ok := d.Paths[specPath(r.Path)]; !ok || item[strings.ToLower(r.Method)] == nil {
+			missing = append(missing, key)
+		}
+	}
+
+	var stale []string
+	for path, item := range d.Paths {
+		for method := range item {
+			if key := strings.ToUpper(method) + " " + path; !registered[key] {
+				stale = append(stale, key)
+			}
+		}
+	}
+
+	if len(missing) == 0 && len(stale) == 0 {
+		return nil
+	}
+
+	sort.Strings(missing)
+	sort.Strings(stale)
+	var problems []string
+	if len(missing) > 0 {
+		problems = append(problems, "routes without a spec entry: "+strings.Join(missing, ", "))
+	}
+	if len(stale) > 0 {
+		problems = append(problems, "spec entries without a route: "+strings.Join(stale, ", "))
+	}
+	return fmt.Errorf("openapi: %s", strings.Join(problems, "; "))
+}
+
+// specPath converts an Echo route path to an OpenAPI path
+func specPath(path string) string {
+	parts := strings.Split(path, "/")
+	for i, p := range parts {
+		switch {
+		case strings.HasPrefix(p, ":"):
+			parts[i] = "{" + p[1:] + "}"
+		case strings.HasSuffix(p, "*"):
+			parts[i] = strings.TrimSuffix(p, "*") + "{path}"
+		}
+	}
+	return strings.Join(parts, "/")
+}
+
+// PathParam returns a required path parameter
+func PathParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "path", Description: description, Required: true, Schema: schema}
+}
+
+// QueryParam returns an optional query parameter
+func QueryParam(name, description string, schema *Schema) Parameter {
+	return Parameter{Name: name, In: "query", Description: description, Schema: schema}
+}
+
+// JSON returns the content of a JSON body
+func JSON(schema *Schema) map[string]MediaType {
+	return map[string]MediaType{"application/json": {Schema: schema}}
+}
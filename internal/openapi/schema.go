@@ -0,0 +1,222 @@
+package openapi
+
+import (
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// Schema is a JSON Schema as used by OpenAPI 3.1. Type is a string, or a
+// list of strings for nullable values.
+type Schema struct {
+	Ref                  string             `json:"$ref,omitempty"`
+	Type                 any                `json:"type,omitempty"`
+	Format               string             `json:"format,omitempty"`
+	Description          string             `json:"description,omitempty"`
+	Enum                 []any              `json:"enum,omitempty"`
+	Default              any                `json:"default,omitempty"`
+	Minimum              *float64           `json:"minimum,omitempty"`
+	Maximum              *float64           `json:"maximum,omitempty"`
+	Items                *Schema            `json:"items,omitempty"`
+	Properties           map[string]*Schema `json:"properties,omitempty"`
+	Required             []string           `json:"required,omitempty"`
+	AdditionalProperties *Schema            `json:"additionalProperties,omitempty"`
+}
+
+// String returns a string schema
+func String(description string) *Schema {
+	return &Schema{Type: "string", Description: description}
+}
+
+// Integer returns an integer schema
+func Integer(description string) *Schema {
+	return &Schema{Type: "integer", Description: description}
+}
+
+// Number returns a number schema
+func Number(description string) *Schema {
+	return &Schema{Type: "number", Description: description}
+}
+
+// Boolean returns a boolean schema
+func Boolean(description string) *Schema {
+	return &Schema{Type: "boolean", Description: description}
+}
+
+// Enum returns a string schema limited to values
+func Enum(description string, values ...string) *Schema {
+	s := String(description)
+	for _, v := range values {
+		s.Enum = append(s.Enum, v)
+	}
+	return s
+}
+
+// ArrayOf returns an array schema of items
+func ArrayOf(items *Schema) *Schema {
+	return &Schema{Type: "array", Items: items}
+}
+
+// Binary returns the schema of a file body
+func Binary() *Schema {
+	return &Schema{Type: "string", Format: "binary"}
+}
+
+// nullable allows null in addition to the type of s
+func nullable(s *Schema) *Schema {
+	if t, ok := s.Type.(string); ok {
+		s.Type = []string{t, "null"}
+	}
+	return s
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	textType     = reflect.TypeOf(pgtype.Text{})
+	int4Type     = reflect.TypeOf(pgtype.Int4{})
+	int8Type     = reflect.TypeOf(pgtype.Int8{})
+	numericType  = reflect.TypeOf(pgtype.Numeric{})
+	boolType     = reflect.TypeOf(pgtype.Bool{})
+	dateType     = reflect.TypeOf(pgtype.Date{})
+	timestampTyp = reflect.TypeOf(pgtype.Timestamp{})
+	timestamptz  = reflect.TypeOf(pgtype.Timestamptz{})
+)
+
+// Schema returns a reference to the schema of the Go value v, registering
+// it and every struct it contains under their type names. The schema
+// follows encoding/json: fields take their json tag names, omitempty
+// fields are optional and embedded structs are flattened.
+func (d *Document) Schema(v any) *Schema {
+	return d.schemaOf(reflect.TypeOf(v))
+}
+
+// Named registers the schema of v under name, for types such as generic
+// envelopes whose Go name does not fit, and returns a reference to it
+func (d *Document) Named(name string, v any, override func(*Schema)) *Schema {
+	s := d.structSchema(reflect.TypeOf(v))
+	if override != nil {
+		override(s)
+	}
+	d.Components.Schemas[name] = s
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+func (d *Document) schemaOf(t reflect.Type) *Schema {
+	switch t {
+	case timeType:
+		return &Schema{Type: "string", Format: "date-time"}
+	case textType:
+		return nullable(String(""))
+	case int4Type, int8Type:
+		return nullable(Integer(""))
+	case numericType:
+		return nullable(Number(""))
+	case boolType:
+		return nullable(Boolean(""))
+	case dateType:
+		return nullable(&Schema{Type: "string", Format: "date"})
+	case timestampTyp, timestamptz:
+		return nullable(&Schema{Type: "string", Format: "date-time"})
+	}
+
+	switch t.Kind() {
+	case reflect.Pointer:
+		s := d.schemaOf(t.Elem())
+		if s.Ref != "" {
+			return s
+		}
+		return nullable(s)
+	case reflect.String:
+		return String("")
+	case reflect.Bool:
+		return Boolean("")
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return Integer("")
+	case reflect.Float32, reflect.Float64:
+		return Number("")
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return &Schema{Type: "string", Format: "byte"}
+		}
+		return ArrayOf(d.schemaOf(t.Elem()))
+	case reflect.Map:
+		return &Schema{Type: "object", AdditionalProperties: d.schemaOf(t.Elem())}
+	case reflect.Struct:
+		return d.ref(t)
+	default:
+		// Interfaces may hold anything
+		return &Schema{}
+	}
+}
+
+// ref registers a struct type as a component and returns a reference
+func (d *Document) ref(t reflect.Type) *Schema {
+	name, ok := d.types[t]
+	if !ok {
+		name = d.componentName(t)
+		d.types[t] = name
+		// Reserve the name first so recursive types terminate
+		d.Components.Schemas[name] = &Schema{}
+		d.Components.Schemas[name] = d.structSchema(t)
+	}
+	return &Schema{Ref: "#/components/schemas/" + name}
+}
+
+// componentName names a struct after its type, prefixing the package name
+// when another package already took the type name
+func (d *Document) componentName(t reflect.Type) string {
+	name := t.Name()
+	if name == "" {
+		name = "Object"
+	}
+	if _, taken := d.Components.Schemas[name]; !taken {
+		return name
+	}
+	pkg := t.PkgPath()
+	pkg = pkg[strings.LastIndex(pkg, "/")+1:]
+	return string(unicode.ToUpper(rune(pkg[0]))) + pkg[1:] + name
+}
+
+// structSchema builds the object schema of a struct
+func (d *Document) structSchema(t reflect.Type) *Schema {
+	s := &Schema{Type: "object", Properties: map[string]*Schema{}}
+	d.addFields(s, t)
+	return s
+}
+
+func (d *Document) addFields(s *Schema, t reflect.Type) {
+	for i := 0; i < t.NumField(); i++ {
+		f := t.Field(i)
+		tag := f.Tag.Get("json")
+		if tag == "-" {
+			continue
+		}
+		name, opts, _ := strings.Cut(tag, ",")
+
+		if f.Anonymous && name == "" {
+			ft := f.Type
+			if ft.Kind() == reflect.Pointer {
+				ft = ft.Elem()
+			}
+			if ft.Kind() == reflect.Struct {
+				d.addFields(s, ft)
+				continue
+			}
+		}
+		if !f.IsExported() {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+
+		s.Properties[name] = d.schemaOf(f.Type)
+		if !strings.Contains(opts, "omitempty") {
+			s.Required = append(s.Required, name)
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package search
+
+// Kind describes the SQL type of an aircraft_data column
+type Kind int
+
+const (
+	KindText Kind = iota
+	KindInteger
+	KindDecimal
+)
+
+// Column describes a column of aircraft_data that can be used in a search
+type Column struct {
+	Name        string
+	Kind        Kind
+	Categorical bool
+}
+
+// Columns lists the searchable columns of aircraft_data in table order.
+// Column names double as query parameter names, so this list is also the
+// whitelist that keeps caller input out of the generated SQL.
+var Columns = []Column{
+	{Name: "icao_code", Kind: KindText, Categorical: true},
+	{Name: "faa_designator", Kind: KindText, Categorical: true},
+	{Name: "manufacturer", Kind: KindText, Categorical: true},
+	{Name: "model_faa", Kind: KindText},
+	{Name: "model_bada", Kind: KindText, Categorical: true},
+	{Name: "physical_class_engine", Kind: KindText, Categorical: true},
+	{Name: "num_engines", Kind: KindInteger, Categorical: true},
+	{Name: "aac", Kind: KindText, Categorical: true},
+	{Name: "aac_minimum", Kind: KindText, Categorical: true},
+	{Name: "aac_maximum", Kind: KindText, Categorical: true},
+	{Name: "adg", Kind: KindText, Categorical: true},
+	{Name: "tdg", Kind: KindText, Categorical: true},
+	{Name: "approach_speed_knot", Kind: KindInteger},
+	{Name: "approach_speed_minimum_knot", Kind: KindInteger},
+	{Name: "approach_speed_maximum_knot", Kind: KindInteger},
+	{Name: "wingspan_ft_without_winglets_sharklets", Kind: KindDecimal},
+	{Name: "wingspan_ft_with_winglets_sharklets", Kind: KindDecimal},
+	{Name: "length_ft", Kind: KindDecimal},
+	{Name: "tail_height_at_oew_ft", Kind: KindDecimal},
+	{Name: "wheelbase_ft", Kind: KindDecimal},
+	{Name: "cockpit_to_main_gear_ft", Kind: KindDecimal},
+	{Name: "main_gear_width_ft", Kind: KindDecimal},
+	{Name: "mtow_lb", Kind: KindInteger},
+	{Name: "malw_lb", Kind: KindInteger},
+	{Name: "main_gear_config", Kind: KindText, Categorical: true},
+	{Name: "icao_wtc", Kind: KindText, Categorical: true},
+	{Name: "parking_area_ft2", Kind: KindDecimal},
+	{Name: "class", Kind: KindText, Categorical: true},
+	{Name: "faa_weight", Kind: KindText, Categorical: true},
+	{Name: "cwt", Kind: KindText, Categorical: true},
+	{Name: "one_half_wake_category", Kind: KindText, Categorical: true},
+	{Name: "two_wake_category_appx_a", Kind: KindText, Categorical: true},
+	{Name: "two_wake_category_appx_b", Kind: KindText, Categorical: true},
+	{Name: "rotor_diameter_ft", Kind: KindDecimal},
+	{Name: "srs", Kind: KindText, Categorical: true},
+	{Name: "lahso", Kind: KindText, Categorical: true},
+	{Name: "faa_registry", Kind: KindText, Categorical: true},
+	{Name: "registration_count", Kind: KindInteger},
+	{Name: "tmfs_operations_fy24", Kind: KindInteger},
+	{Name: "remarks", Kind: KindText},
+	{Name: "last_update", Kind: KindText},
+}
+
+// LookupColumn returns the column with the given name
+func LookupColumn(name string) (Column, bool) {
+	for _, col := range Columns {
+		if col.Name == name {
+			return col, true
+		}
+	}
+	return Column{}, false
+}
@@ -0,0 +1,113 @@
+package search
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// ErrInvalidCursor is returned when a cursor cannot be decoded or does not
+// belong to the requested sort order
+var ErrInvalidCursor = errors.New("invalid cursor")
+
+// Cursor marks a position in an ordered result set. It carries the sort
+// key values of a boundary row plus its id, and is handed to callers as an
+// opaque string.
+type Cursor struct {
+	Sort   string    `json:"s"`
+	Values []*string `json:"v"`
+	ID     int32     `json:"id"`
+	Before bool      `json:"b,omitempty"`
+}
+
+// Encode returns the opaque string form of the cursor
+func (c Cursor) Encode() string {
+	data, _ := json.Marshal(c)
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// DecodeCursor parses an opaque cursor and checks it matches the sort order
+func DecodeCursor(raw string, sort Sort) (*Cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(raw)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	var c Cursor
+	if err := json.Unmarshal(data, &c); err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	if c.Sort != sort.effective().String() || len(c.Values) != len(sort.keys()) {
+		return nil, fmt.Errorf("%w: cursor does not match sort order", ErrInvalidCursor)
+	}
+
+	return &c, nil
+}
+
+// newCursor builds the cursor positioned at the given row
+func newCursor(sort Sort, a db.AircraftDatum, before bool) (Cursor, error) {
+	// Round-trip through JSON to read columns by name; pgtype values
+	// marshal to plain strings and decimal numbers or null
+	data, err := json.Marshal(a)
+	if err != nil {
+		return Cursor{}, err
+	}
+	var row map[string]json.RawMessage
+	if err := json.Unmarshal(data, &row); err != nil {
+		return Cursor{}, err
+	}
+
+	keys := sort.keys()
+	c := Cursor{
+		Sort:   sort.effective().String(),
+		Values: make([]*string, len(keys)),
+		ID:     a.ID,
+		Before: before,
+	}
+
+	for i, field := range keys {
+		raw := row[field.Column]
+		if len(raw) == 0 || string(raw) == "null" {
+			continue
+		}
+
+		var v string
+		if col, _ := LookupColumn(field.Column); col.Kind == KindText {
+			if err := json.Unmarshal(raw, &v); err != nil {
+				return Cursor{}, err
+			}
+		} else {
+			v = string(raw)
+		}
+		c.Values[i] = &v
+	}
+
+	return c, nil
+}
+
+// cursorValue converts a stored cursor value into a query argument of the
+// column's type
+func cursorValue(col Column, v string) (any, error) {
+	switch col.Kind {
+	case KindInteger:
+		n, err := strconv.ParseInt(v, 10, 32)
+		if err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return int32(n), nil
+	case KindDecimal:
+		var num pgtype.Numeric
+		if err := num.Scan(v); err != nil {
+			return nil, ErrInvalidCursor
+		}
+		return num, nil
+	default:
+		return v, nil
+	}
+}
@@ -0,0 +1,227 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+)
+
+const (
+	// FacetLimit caps the number of buckets returned for a terms facet
+	FacetLimit = 50
+	// HistogramBuckets is the number of buckets a histogram facet aims for
+	HistogramBuckets = 10
+)
+
+// Facet types
+const (
+	FacetTerms     = "terms"
+	FacetHistogram = "histogram"
+)
+
+// Facet holds the bucket counts of one column. Categorical columns get a
+// terms facet counting each value, ordered by count; numeric columns get a
+// histogram of equal width buckets, ordered by value. Histograms of
+// dimensional columns are in the units of the query, named by Unit.
+type Facet struct {
+	Type    string   `json:"type"`
+	Unit    string   `json:"unit,omitempty"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is a facet bucket. Terms buckets set Value; histogram buckets
+// cover Min inclusive to Max exclusive.
+type Bucket struct {
+	Value string   `json:"value,omitempty"`
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// ParseFacets parses a comma separated list of facet columns. Categorical
+// and numeric columns are accepted; free text columns are not.
+func ParseFacets(raw string) ([]string, error) {
+	var names []string
+	seen := map[string]bool{}
+
+	for _, name := range strings.Split(raw, ",") {
+		name = strings.ToLower(strings.TrimSpace(name))
+		if name == "" || seen[name] {
+			continue
+		}
+
+		col, ok := LookupColumn(name)
+		if !ok || (!col.Categorical && col.Kind == KindText) {
+			return nil, fmt.Errorf("cannot facet on %q", name)
+		}
+
+		seen[name] = true
+		names = append(names, name)
+	}
+
+	return names, nil
+}
+
+// Facets counts the results of the query per bucket of each named column.
+// Each facet ignores the query's own filter on that column, so a sidebar
+// keeps offering the other values of a column once one is selected.
+func Facets(ctx context.Context, conn db.DBTX, q Query, names []string) (map[string]Facet, error) {
+	facets := make(map[string]Facet, len(names))
+
+	for _, name := range names {
+		col, ok := LookupColumn(name)
+		if !ok {
+			return nil, fmt.Errorf("cannot facet on %q", name)
+		}
+
+		fq := q
+		fq.Filter = q.Filter.without(col.Name)
+
+		var (
+			facet Facet
+			err   error
+		)
+		if col.Categorical {
+			facet, err = termsFacet(ctx, conn, fq, col)
+		} else {
+			facet, err = histogramFacet(ctx, conn, fq, col)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("facet %s: %w", col.Name, err)
+		}
+		facets[col.Name] = facet
+	}
+
+	return facets, nil
+}
+
+// termsFacet counts the most common values of a categorical column
+func termsFacet(ctx context.Context, conn db.DBTX, q Query, col Column) (Facet, error) {
+	b := &builder{}
+	conds := append(b.conditions(q), col.Name+" IS NOT NULL")
+
+	sql := fmt.Sprintf("SELECT %[1]s::text, COUNT(*)%[2]s%[3]s GROUP BY %[1]s ORDER BY COUNT(*) DESC, %[1]s LIMIT %[4]s",
+		col.Name, b.from(q), whereClause(conds), b.arg(FacetLimit))
+
+	rows, err := conn.Query(ctx, sql, b.args...)
+	if err != nil {
+		return Facet{}, err
+	}
+	defer rows.Close()
+
+	facet := Facet{Type: FacetTerms, Buckets: []Bucket{}}
+	for rows.Next() {
+		var bucket Bucket
+		if err := rows.Scan(&bucket.Value, &bucket.Count); err != nil {
+			return Facet{}, err
+		}
+		facet.Buckets = append(facet.Buckets, bucket)
+	}
+	return facet, rows.Err()
+}
+
+// histogramFacet counts the values of a numeric column in equal width
+// buckets. The width is rounded to 1, 2 or 5 times a power of ten so the
+// bucket bounds make readable filters.
+func histogramFacet(ctx context.Context, conn db.DBTX, q Query, col Column) (Facet, error) {
+	facet := Facet{Type: FacetHistogram, Buckets: []Bucket{}}
+
+	// Dimensional columns are bucketed in the units of the query, so the
+	// bounds make readable filters in that system
+	value := col.Name + "::float8"
+	if quantity, ok := units.Fields[col.Name]; ok {
+		facet.Unit = quantity.Unit(q.Units)
+		if factor := quantity.Factor(q.Units); factor != 1 {
+			value = fmt.Sprintf("(%s * %s)", value, strconv.FormatFloat(factor, 'g', -1, 64))
+		}
+	}
+
+	b := &builder{}
+	sql := fmt.Sprintf("SELECT MIN(%[1]s), MAX(%[1]s)%[2]s%[3]s",
+		value, b.from(q), whereClause(b.conditions(q)))
+
+	var lo, hi *float64
+	if err := conn.QueryRow(ctx, sql, b.args...).Scan(&lo, &hi); err != nil {
+		return Facet{}, err
+	}
+	if lo == nil || hi == nil {
+		return facet, nil
+	}
+
+	width := bucketWidth(*hi - *lo)
+	if col.Kind == KindInteger && width < 1 {
+		width = 1
+	}
+
+	b = &builder{}
+	conds := append(b.conditions(q), col.Name+" IS NOT NULL")
+	w := b.arg(width)
+	sql = fmt.Sprintf("SELECT FLOOR(%[1]s / %[2]s::float8) * %[2]s::float8 AS bucket, COUNT(*)%[3]s%[4]s GROUP BY bucket ORDER BY bucket",
+		value, w, b.from(q), whereClause(conds))
+
+	rows, err := conn.Query(ctx, sql, b.args...)
+	if err != nil {
+		return Facet{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var start float64
+		var count int64
+		if err := rows.Scan(&start, &count); err != nil {
+			return Facet{}, err
+		}
+		start = tidy(start)
+		end := tidy(start + width)
+		facet.Buckets = append(facet.Buckets, Bucket{Min: &start, Max: &end, Count: count})
+	}
+	return facet, rows.Err()
+}
+
+// bucketWidth picks a readable bucket width splitting span into about
+// HistogramBuckets buckets
+func bucketWidth(span float64) float64 {
+	if span <= 0 {
+		return 1
+	}
+
+	raw := span / HistogramBuckets
+	magnitude := math.Pow(10, math.Floor(math.Log10(raw)))
+	for _, step := range []float64{1, 2, 5} {
+		if step*magnitude >= raw {
+			return tidy(step * magnitude)
+		}
+	}
+	return tidy(10 * magnitude)
+}
+
+// tidy removes floating point noise from bucket bounds, so that e.g. a
+// bound of 0.3 is not rendered as 0.30000000000000004
+func tidy(v float64) float64 {
+	t, _ := strconv.ParseFloat(strconv.FormatFloat(v, 'g', 12, 64), 64)
+	return t
+}
+
+// without returns a copy of the filter with no condition on the column
+func (f Filter) without(name string) Filter {
+	out := Filter{
+		Values: make(map[string][]string, len(f.Values)),
+		Ranges: make(map[string]Range, len(f.Ranges)),
+	}
+	for k, v := range f.Values {
+		if k != name {
+			out.Values[k] = v
+		}
+	}
+	for k, r := range f.Ranges {
+		if k != name {
+			out.Ranges[k] = r
+		}
+	}
+	return out
+}
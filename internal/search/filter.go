@@ -0,0 +1,112 @@
+package search
+
+import (
+	"fmt"
+	"math"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Filter holds the structured attribute filters of a search.
+// Values lists the accepted values per categorical column; a row matches
+// when it equals any of the values of every filtered column and lies
+// within every range.
+type Filter struct {
+	Values map[string][]string
+	Ranges map[string]Range
+}
+
+// Range is an inclusive bound on a numeric column. A nil bound is open.
+type Range struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// IsEmpty reports whether the filter has no conditions
+func (f Filter) IsEmpty() bool {
+	return len(f.Values) == 0 && len(f.Ranges) == 0
+}
+
+// ParseFilter reads the column filters from query parameters.
+// Categorical columns are addressed by their name, e.g. adg=III&aac=C,D,
+// and multiple values may be given comma separated or by repeating the
+// parameter. Numeric columns take inclusive bounds prefixed with min_ and
+// max_, e.g. max_wingspan_ft_with_winglets_sharklets=118.
+func ParseFilter(params url.Values) (Filter, error) {
+	filter := Filter{
+		Values: map[string][]string{},
+		Ranges: map[string]Range{},
+	}
+
+	for _, col := range Columns {
+		if !col.Categorical {
+			if col.Kind == KindText {
+				continue
+			}
+
+			r, err := parseRange(params, col.Name)
+			if err != nil {
+				return Filter{}, err
+			}
+			if r.Min != nil || r.Max != nil {
+				filter.Ranges[col.Name] = r
+			}
+			continue
+		}
+
+		var values []string
+		for _, raw := range params[col.Name] {
+			for _, v := range strings.Split(raw, ",") {
+				v = strings.TrimSpace(v)
+				if v == "" {
+					continue
+				}
+				if col.Kind == KindInteger {
+					if _, err := strconv.ParseInt(v, 10, 32); err != nil {
+						return Filter{}, fmt.Errorf("invalid value %q for %s: must be an integer", v, col.Name)
+					}
+				}
+				values = append(values, v)
+			}
+		}
+
+		if len(values) > 0 {
+			filter.Values[col.Name] = values
+		}
+	}
+
+	return filter, nil
+}
+
+// parseRange reads the min_ and max_ bounds of a numeric column
+func parseRange(params url.Values, name string) (Range, error) {
+	var r Range
+
+	parse := func(key string) (*float64, error) {
+		raw := strings.TrimSpace(params.Get(key))
+		if raw == "" {
+			return nil, nil
+		}
+		// Allow thousands separators as in "40,000"
+		val, err := strconv.ParseFloat(strings.ReplaceAll(raw, ",", ""), 64)
+		if err != nil || math.IsNaN(val) || math.IsInf(val, 0) {
+			return nil, fmt.Errorf("invalid value %q for %s: must be a number", raw, key)
+		}
+		return &val, nil
+	}
+
+	var err error
+	if r.Min, err = parse("min_" + name); err != nil {
+		return Range{}, err
+	}
+	if r.Max, err = parse("max_" + name); err != nil {
+		return Range{}, err
+	}
+
+	if r.Min != nil && r.Max != nil && *r.Min > *r.Max {
+		return Range{}, fmt.Errorf("invalid range for %s: min_%s is greater than max_%s", name, name, name)
+	}
+
+	return r, nil
+}
@@ -0,0 +1,356 @@
+package search
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
+	"github.com/jackc/pgx/v5"
+)
+
+// Query describes a search over aircraft_data.
+// When Cursor is set the page is read relative to it and Offset is ignored.
+// A non-zero Version searches the snapshot of that dataset version in
+// aircraft_data_history instead of the live table. Units is the system the
+// range bounds of Filter and the histogram buckets of facets are in; the
+// zero value is the imperial units the columns are stored in.
+type Query struct {
+	Text    string
+	Filter  Filter
+	Sort    Sort
+	Cursor  *Cursor
+	Limit   int32
+	Offset  int32
+	Version int32
+	Units   units.System
+}
+
+// Page is a page of search results with cursors to its neighbours.
+// A cursor is empty when there is no page in that direction.
+type Page struct {
+	Aircraft   []db.AircraftDatum
+	NextCursor string
+	PrevCursor string
+}
+
+// Aircraft returns the page of aircraft matching the query using offset paging
+func Aircraft(ctx context.Context, conn db.DBTX, q Query) ([]db.AircraftDatum, error) {
+	b := &builder{}
+	where := whereClause(b.conditions(q))
+
+	sql := "SELECT *" + b.from(q) + where + b.orderBy(q, false) +
+		" LIMIT " + b.arg(q.Limit) + " OFFSET " + b.arg(q.Offset)
+
+	return b.collect(ctx, conn, sql)
+}
+
+// AircraftPage returns the page of aircraft matching the query along with
+// cursors for keyset pagination. Without a cursor it starts at Offset.
+func AircraftPage(ctx context.Context, conn db.DBTX, q Query) (Page, error) {
+	b := &builder{}
+	conds := b.conditions(q)
+
+	if q.Cursor != nil && q.ranked() {
+		return Page{}, fmt.Errorf("%w: cursors are not available for relevance ordering, pass an explicit sort", ErrInvalidCursor)
+	}
+
+	before := q.Cursor != nil && q.Cursor.Before
+	if q.Cursor != nil {
+		cond, err := b.keyset(q.Sort, q.Cursor)
+		if err != nil {
+			return Page{}, err
+		}
+		conds = append(conds, cond)
+	}
+
+	// Read one extra row to learn whether another page follows
+	sql := "SELECT *" + b.from(q) + whereClause(conds) + b.orderBy(q, before) +
+		" LIMIT " + b.arg(q.Limit+1)
+	if q.Cursor == nil {
+		sql += " OFFSET " + b.arg(q.Offset)
+	}
+
+	aircraft, err := b.collect(ctx, conn, sql)
+	if err != nil {
+		return Page{}, err
+	}
+
+	more := len(aircraft) > int(q.Limit)
+	if more {
+		aircraft = aircraft[:q.Limit]
+	}
+
+	hasNext, hasPrev := more, q.Cursor != nil || q.Offset > 0
+	if q.ranked() {
+		// Relevance is computed per query, so there is no key to resume from
+		hasNext, hasPrev = false, false
+	} else if before {
+		// Rows were read backwards from the cursor
+		slices.Reverse(aircraft)
+		hasNext, hasPrev = true, more
+	}
+
+	page := Page{Aircraft: aircraft}
+	if len(aircraft) == 0 {
+		return page, nil
+	}
+
+	if hasNext {
+		c, err := newCursor(q.Sort, aircraft[len(aircraft)-1], false)
+		if err != nil {
+			return Page{}, err
+		}
+		page.NextCursor = c.Encode()
+	}
+	if hasPrev {
+		c, err := newCursor(q.Sort, aircraft[0], true)
+		if err != nil {
+			return Page{}, err
+		}
+		page.PrevCursor = c.Encode()
+	}
+
+	return page, nil
+}
+
+// Each calls fn for every aircraft matching the query in sort order,
+// ignoring Limit, Offset and Cursor. Rows are scanned one at a time so the
+// full result set is never held in memory.
+func Each(ctx context.Context, conn db.DBTX, q Query, fn func(db.AircraftDatum) error) error {
+	b := &builder{}
+	sql := "SELECT *" + b.from(q) + whereClause(b.conditions(q)) + b.orderBy(q, false)
+
+	rows, err := conn.Query(ctx, sql, b.args...)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		a, err := pgx.RowToStructByName[db.AircraftDatum](rows)
+		if err != nil {
+			return err
+		}
+		if err := fn(a); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+// ranked reports whether results are ordered by relevance to the search
+// text, which is the default whenever text is given without a sort
+func (q Query) ranked() bool {
+	return q.Text != "" && q.Sort.relevance()
+}
+
+// Count returns the total number of aircraft matching the query
+func Count(ctx context.Context, conn db.DBTX, q Query) (int64, error) {
+	b := &builder{}
+	sql := "SELECT COUNT(*)" + b.from(q) + whereClause(b.conditions(q))
+
+	var count int64
+	err := conn.QueryRow(ctx, sql, b.args...).Scan(&count)
+	return count, err
+}
+
+// builder accumulates SQL conditions and their positional arguments
+type builder struct {
+	args []any
+}
+
+// arg registers a positional argument and returns its placeholder
+func (b *builder) arg(v any) string {
+	b.args = append(b.args, v)
+	return "$" + strconv.Itoa(len(b.args))
+}
+
+// from renders the FROM clause of the query. A version snapshot is
+// selected under the name aircraft_data so the rest of the SQL applies
+// unchanged.
+func (b *builder) from(q Query) string {
+	if q.Version == 0 {
+		return " FROM aircraft_data"
+	}
+
+	columns := make([]string, 0, len(Columns)+3)
+	columns = append(columns, "id")
+	for _, col := range Columns {
+		columns = append(columns, col.Name)
+	}
+	columns = append(columns, "created_at", "updated_at")
+
+	return fmt.Sprintf(" FROM (SELECT %s FROM aircraft_data_history WHERE version_id = %s) AS aircraft_data",
+		strings.Join(columns, ", "), b.arg(q.Version))
+}
+
+// collect runs a select over aircraft_data and scans every row
+func (b *builder) collect(ctx context.Context, conn db.DBTX, sql string) ([]db.AircraftDatum, error) {
+	rows, err := conn.Query(ctx, sql, b.args...)
+	if err != nil {
+		return nil, err
+	}
+
+	aircraft, err := pgx.CollectRows(rows, pgx.RowToStructByName[db.AircraftDatum])
+	if err != nil {
+		return nil, err
+	}
+	if aircraft == nil {
+		aircraft = []db.AircraftDatum{}
+	}
+	return aircraft, nil
+}
+
+// storedValue converts a bound given in the units of the query to the
+// units the column is stored in
+func (q Query) storedValue(col Column, v float64) float64 {
+	if quantity, ok := units.Fields[col.Name]; ok {
+		return quantity.ToImperial(v, q.Units)
+	}
+	return v
+}
+
+// conditions renders the search text and filters as SQL conditions
+func (b *builder) conditions(q Query) []string {
+	var conds []string
+
+	if q.Text != "" {
+		// A row matches when the whole text is a substring of a code,
+		// manufacturer or model, when every word prefixes a word of the
+		// document, or when the text is similar enough to survive typos
+		term := b.arg("%" + strings.ToUpper(q.Text) + "%")
+		matches := []string{fmt.Sprintf(
+			"UPPER(icao_code) LIKE %[1]s OR UPPER(faa_designator) LIKE %[1]s OR UPPER(manufacturer) LIKE %[1]s OR UPPER(model_faa) LIKE %[1]s",
+			term)}
+		if ts := tsQuery(q.Text); ts != "" {
+			matches = append(matches, fmt.Sprintf("%s @@ to_tsquery('simple', %s)", vector, b.arg(ts)))
+		}
+		matches = append(matches, fmt.Sprintf("%s %%> %s", document, b.arg(q.Text)))
+		conds = append(conds, "("+strings.Join(matches, " OR ")+")")
+	}
+
+	// Iterate the column whitelist rather than the map so the generated SQL
+	// is stable and only ever references known column names
+	for _, col := range Columns {
+		if r, ok := q.Filter.Ranges[col.Name]; ok {
+			if r.Min != nil {
+				conds = append(conds, fmt.Sprintf("%s >= %s::numeric", col.Name, b.arg(q.storedValue(col, *r.Min))))
+			}
+			if r.Max != nil {
+				conds = append(conds, fmt.Sprintf("%s <= %s::numeric", col.Name, b.arg(q.storedValue(col, *r.Max))))
+			}
+		}
+
+		values, ok := q.Filter.Values[col.Name]
+		if !ok {
+			continue
+		}
+
+		switch col.Kind {
+		case KindInteger:
+			ints := make([]int32, 0, len(values))
+			for _, v := range values {
+				n, err := strconv.ParseInt(v, 10, 32)
+				if err != nil {
+					continue
+				}
+				ints = append(ints, int32(n))
+			}
+			conds = append(conds, fmt.Sprintf("%s = ANY(%s)", col.Name, b.arg(ints)))
+		default:
+			upper := make([]string, len(values))
+			for i, v := range values {
+				upper[i] = strings.ToUpper(v)
+			}
+			conds = append(conds, fmt.Sprintf("UPPER(%s) = ANY(%s)", col.Name, b.arg(upper)))
+		}
+	}
+
+	return conds
+}
+
+// orderBy renders the ORDER BY clause for the query. Ranked queries put
+// exact ICAO or FAA code matches first, then order by full-text rank plus
+// trigram similarity, before falling back to the regular sort keys.
+func (b *builder) orderBy(q Query, reverse bool) string {
+	order := q.Sort.orderBy(reverse)
+	if !q.ranked() {
+		return order
+	}
+
+	upper := b.arg(strings.ToUpper(q.Text))
+	rank := fmt.Sprintf("word_similarity(%s, %s)", b.arg(q.Text), document)
+	if ts := tsQuery(q.Text); ts != "" {
+		rank = fmt.Sprintf("ts_rank(%s, to_tsquery('simple', %s)) + %s", vector, b.arg(ts), rank)
+	}
+
+	return fmt.Sprintf(" ORDER BY (UPPER(icao_code) = %[1]s OR UPPER(faa_designator) = %[1]s) IS TRUE DESC, %[2]s DESC, ",
+		upper, rank) + strings.TrimPrefix(order, " ORDER BY ")
+}
+
+// keyset renders the condition selecting the rows that come after the
+// cursor row in sort order, or before it for a backwards cursor. Nulls
+// sort last, so a null key has nothing after it but every value before it.
+func (b *builder) keyset(sort Sort, c *Cursor) (string, error) {
+	var ors, equal []string
+
+	for i, field := range sort.keys() {
+		col, _ := LookupColumn(field.Column)
+
+		var val string
+		if v := c.Values[i]; v != nil {
+			arg, err := cursorValue(col, *v)
+			if err != nil {
+				return "", err
+			}
+			val = b.arg(arg)
+		}
+
+		// Ascending rows after the cursor are greater; flipped for
+		// descending fields and for backwards cursors
+		op := ">"
+		if field.Desc != c.Before {
+			op = "<"
+		}
+
+		var beyond string
+		switch {
+		case val == "" && !c.Before:
+		case val == "":
+			beyond = col.Name + " IS NOT NULL"
+		case !c.Before:
+			beyond = fmt.Sprintf("(%s %s %s OR %s IS NULL)", col.Name, op, val, col.Name)
+		default:
+			beyond = fmt.Sprintf("%s %s %s", col.Name, op, val)
+		}
+		if beyond != "" {
+			ors = append(ors, "("+strings.Join(append(slices.Clone(equal), beyond), " AND ")+")")
+		}
+
+		if val == "" {
+			equal = append(equal, col.Name+" IS NULL")
+		} else {
+			equal = append(equal, fmt.Sprintf("%s = %s", col.Name, val))
+		}
+	}
+
+	op := ">"
+	if sort.idDesc() != c.Before {
+		op = "<"
+	}
+	ors = append(ors, "("+strings.Join(append(equal, "id "+op+" "+b.arg(c.ID)), " AND ")+")")
+
+	return "(" + strings.Join(ors, " OR ") + ")", nil
+}
+
+// whereClause joins conditions into a WHERE clause, or an empty string
+func whereClause(conds []string) string {
+	if len(conds) == 0 {
+		return ""
+	}
+	return " WHERE " + strings.Join(conds, " AND ")
+}
@@ -0,0 +1,162 @@
+package search
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SortField orders results by a single column
+type SortField struct {
+	Column string
+	Desc   bool
+}
+
+// Sort is an ordered list of sort fields. Results are always tie-broken on
+// id so paging through them is deterministic.
+type Sort []SortField
+
+// DefaultSort is the ordering used when the caller does not choose one
+var DefaultSort = Sort{
+	{Column: "manufacturer"},
+	{Column: "model_faa"},
+}
+
+// relevanceSort is the pseudo column ordering results by search relevance
+const relevanceSort = "relevance"
+
+// ParseSort parses a sort parameter of the form
+// "mtow_lb:desc,wingspan_ft_with_winglets_sharklets:asc". The direction
+// defaults to ascending. Only whitelisted columns are accepted, plus
+// "relevance" which is always descending and must come first.
+func ParseSort(raw string) (Sort, error) {
+	var sort Sort
+
+	for _, part := range strings.Split(raw, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+
+		name, dir, _ := strings.Cut(part, ":")
+		name = strings.ToLower(strings.TrimSpace(name))
+
+		if !isSortable(name) {
+			return nil, fmt.Errorf("cannot sort by %q", name)
+		}
+
+		field := SortField{Column: name}
+		switch strings.ToLower(strings.TrimSpace(dir)) {
+		case "", "asc":
+		case "desc":
+			field.Desc = true
+		default:
+			return nil, fmt.Errorf("invalid sort direction %q for %s: must be asc or desc", dir, name)
+		}
+
+		if name == relevanceSort {
+			if len(sort) > 0 {
+				return nil, fmt.Errorf("relevance must be the first sort key")
+			}
+			field.Desc = true
+		}
+
+		sort = append(sort, field)
+	}
+
+	return sort, nil
+}
+
+// String renders the sort back into its parameter form
+func (s Sort) String() string {
+	parts := make([]string, len(s))
+	for i, field := range s {
+		dir := "asc"
+		if field.Desc {
+			dir = "desc"
+		}
+		parts[i] = field.Column + ":" + dir
+	}
+	return strings.Join(parts, ",")
+}
+
+// relevance reports whether the sort asks for relevance ordering, which is
+// also the default when no sort was given
+func (s Sort) relevance() bool {
+	return len(s) == 0 || s[0].Column == relevanceSort
+}
+
+// effective returns the column sort actually applied after any relevance
+// ordering, falling back to DefaultSort
+func (s Sort) effective() Sort {
+	var fields Sort
+	for _, field := range s {
+		if field.Column != relevanceSort {
+			fields = append(fields, field)
+		}
+	}
+	if len(fields) == 0 {
+		return DefaultSort
+	}
+	return fields
+}
+
+// keys returns the effective sort fields before the id tiebreak. Since id
+// is unique, any field listed after it can never affect the order.
+func (s Sort) keys() Sort {
+	var keys Sort
+	for _, field := range s.effective() {
+		if field.Column == "id" {
+			break
+		}
+		keys = append(keys, field)
+	}
+	return keys
+}
+
+// idDesc reports whether the id tiebreak runs descending
+func (s Sort) idDesc() bool {
+	for _, field := range s {
+		if field.Column == "id" {
+			return field.Desc
+		}
+	}
+	return false
+}
+
+// orderBy renders the ORDER BY clause. When reverse is set every direction
+// is flipped, which is used to read the page before a cursor.
+func (s Sort) orderBy(reverse bool) string {
+	keys := s.keys()
+
+	parts := make([]string, 0, len(keys)+1)
+	for _, field := range keys {
+		if field.Desc != reverse {
+			parts = append(parts, field.Column+" DESC")
+		} else {
+			parts = append(parts, field.Column+" ASC")
+		}
+		if reverse {
+			parts[len(parts)-1] += " NULLS FIRST"
+		} else {
+			parts[len(parts)-1] += " NULLS LAST"
+		}
+	}
+
+	// Tiebreak on the primary key, honoring an explicit id direction
+	if s.idDesc() != reverse {
+		parts = append(parts, "id DESC")
+	} else {
+		parts = append(parts, "id ASC")
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
+
+// isSortable reports whether name is a whitelisted sort column
+func isSortable(name string) bool {
+	if name == "id" || name == relevanceSort {
+		return true
+	}
+	_, ok := LookupColumn(name)
+	return ok
+}
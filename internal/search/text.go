@@ -0,0 +1,36 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// document is the text matched by full-text and trigram search. It must stay
+// identical to the expression indexed by the search indexes migration, or
+// PostgreSQL will not use those indexes.
+const document = "(COALESCE(manufacturer, '') || ' ' || COALESCE(model_faa, '') || ' ' || " +
+	"COALESCE(model_bada, '') || ' ' || COALESCE(icao_code, '') || ' ' || " +
+	"COALESCE(faa_designator, '') || ' ' || COALESCE(remarks, ''))"
+
+// vector is the full-text search vector of the document
+const vector = "to_tsvector('simple', " + document + ")"
+
+// tokens splits search text into the words used for full-text matching.
+// Anything that is not a letter or digit separates words, which matches how
+// the 'simple' text search configuration splits e.g. "737-8".
+func tokens(text string) []string {
+	return strings.FieldsFunc(strings.ToLower(text), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+}
+
+// tsQuery builds a to_tsquery expression requiring every token as a prefix,
+// so "boeing 737 max" becomes "boeing:* & 737:* & max:*". Tokens only
+// contain letters and digits, so no tsquery syntax can be injected.
+func tsQuery(text string) string {
+	words := tokens(text)
+	for i, word := range words {
+		words[i] = word + ":*"
+	}
+	return strings.Join(words, " & ")
+}
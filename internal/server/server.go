@@ -0,0 +1,110 @@
+// Package server assembles the Echo server: its middleware and the routes
+// of the web UI and API. It is shared by cmd/web and by tests that run the
+// real router.
+package server
+
+import (
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/handler"
+	"github.com/dukerupert/faa-aircraft-search/internal/middleware"
+	"github.com/dukerupert/faa-aircraft-search/internal/openapi"
+	"github.com/labstack/echo/v4"
+	echomiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// New returns an Echo instance with the middleware and routes of the
+// server
+func New(h *handler.Handlers) *echo.Echo {
+	e := echo.New()
+
+	// Middleware
+	e.Use(echomiddleware.Logger())
+	e.Use(echomiddleware.Recover())
+	e.Use(echomiddleware.CORS())
+
+	// Prometheus metrics middleware
+	e.Use(middleware.PrometheusMiddleware())
+
+	// Request timeout middleware. It buffers the response, so exports,
+	// which stream under their own longer deadline, skip it.
+	e.Use(echomiddleware.TimeoutWithConfig(echomiddleware.TimeoutConfig{
+		Skipper: handler.IsExport,
+		Timeout: 30 * time.Second,
+	}))
+
+	// Unit system from the units query parameter or cookie
+	e.Use(middleware.Units(handler.InvalidUnits))
+
+	registerRoutes(e, h)
+
+	return e
+}
+
+// registerRoutes registers every route of the server. Each route must also
+// be described in handler.OpenAPI; TestOpenAPICoversRoutes checks that.
+func registerRoutes(e *echo.Echo, h *handler.Handlers) {
+	// Static file serving (for any additional static assets)
+	e.Static("/static", "web/static")
+
+	// Metrics endpoint (exclude from metrics middleware to avoid recursion)
+	e.GET("/metrics", echo.WrapHandler(promhttp.Handler()))
+
+	// Web routes (HTML pages)
+	e.GET("/", h.Home)
+	e.GET("/search", h.Search)
+	e.GET("/aircraft-list", h.AircraftList)
+	e.GET("/aircraft-details/:id", h.AircraftDetails)
+	e.GET("/compare", h.Compare)
+	e.GET("/compatibility", h.CompatibilityPage)
+	e.GET("/wake", h.WakeMatrix)
+	e.POST("/units", h.SetUnits)
+
+	// Base health check route
+	e.GET("/health", h.HealthCheck)
+
+	// OpenAPI document and the API reference rendering it
+	e.GET("/api/openapi.json", h.OpenAPISpec)
+	e.GET("/api/docs", h.APIDocs)
+	e.StaticFS("/api/docs/", echo.MustSubFS(openapi.Docs, "docs"))
+
+	// API v1 routes
+	v1 := e.Group("/api/v1")
+	{
+		aircraft := v1.Group("/aircraft")
+		{
+			aircraft.GET("/search", h.SearchAircraft)
+			aircraft.POST("/batch", h.BatchLookup)
+			aircraft.GET("/compare", h.CompareAircraft)
+			aircraft.GET("/icao/:code", h.GetAircraftByICAO)
+			aircraft.GET("/faa/:designator", h.GetAircraftByFAA)
+			aircraft.GET("/:id", h.GetAircraft)
+			aircraft.POST("/:id/gates", h.AircraftGates)
+		}
+
+		v1.POST("/classify", h.Classify)
+		v1.GET("/classify/mismatches", h.ClassificationMismatches)
+		v1.GET("/compatibility", h.Compatibility)
+		v1.POST("/gates/fit", h.GateFit)
+		v1.GET("/wake/separation", h.WakeSeparation)
+		v1.GET("/wake/schemes", h.WakeSchemes)
+		v1.GET("/versions", h.ListVersions)
+		v1.POST("/diff", h.DiffRelease)
+	}
+
+	// API v2 routes, serving the typed aircraft representation
+	v2 := e.Group("/api/v2")
+	{
+		aircraft := v2.Group("/aircraft")
+		{
+			aircraft.GET("/search", h.SearchAircraftV2)
+			aircraft.GET("/icao/:code", h.GetAircraftByICAOV2)
+			aircraft.GET("/faa/:designator", h.GetAircraftByFAAV2)
+			aircraft.GET("/:id", h.GetAircraftV2)
+		}
+	}
+
+	// Static file serving (for any additional static assets)
+	e.Static("/static", "web/static")
+}
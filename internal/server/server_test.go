@@ -0,0 +1,32 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/handler"
+	"github.com/labstack/echo/v4"
+)
+
+// TestOpenAPICoversRoutes fails when a route is registered without an
+// entry in the OpenAPI document, or an entry outlives its route. Routes
+// are only registered, so no database is needed.
+func TestOpenAPICoversRoutes(t *testing.T) {
+	e := echo.New()
+	registerRoutes(e, handler.New(nil))
+
+	if err := handler.OpenAPI().Check(e.Routes()); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// TestOpenAPIReportsUndocumentedRoute guards the check itself
+func TestOpenAPIReportsUndocumentedRoute(t *testing.T) {
+	e := echo.New()
+	registerRoutes(e, handler.New(nil))
+	e.GET("/api/v1/undocumented", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	if err := handler.OpenAPI().Check(e.Routes()); err == nil {
+		t.Fatal("expected an undocumented route to be reported")
+	}
+}
@@ -0,0 +1,364 @@
+// Package units converts the dimensional fields of aircraft between the
+// imperial units they are stored in and metric units.
+package units
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+// System is a system of units
+type System string
+
+const (
+	Imperial System = "imperial"
+	Metric   System = "metric"
+)
+
+// CookieName is the cookie that keeps the unit system chosen in the web UI
+const CookieName = "units"
+
+// Parse validates a unit system name; empty means imperial
+func Parse(raw string) (System, error) {
+	switch s := System(strings.ToLower(strings.TrimSpace(raw))); s {
+	case "":
+		return Imperial, nil
+	case Imperial, Metric:
+		return s, nil
+	default:
+		return "", fmt.Errorf("unsupported units %q: must be metric or imperial", raw)
+	}
+}
+
+// Quantity is a kind of measurement
+type Quantity int
+
+const (
+	Length Quantity = iota
+	Area
+	Mass
+	Speed
+)
+
+// quantities holds the unit labels, the factor from imperial to metric and
+// the decimals metric values are rounded to
+var quantities = map[Quantity]struct {
+	imperial, metric string
+	factor           float64
+	decimals         int
+}{
+	Length: {"ft", "m", 0.3048, 2},
+	Area:   {"ft²", "m²", 0.09290304, 1},
+	Mass:   {"lb", "kg", 0.45359237, 0},
+	Speed:  {"kt", "km/h", 1.852, 0},
+}
+
+// Unit returns the unit label of the quantity in a system
+func (q Quantity) Unit(s System) string {
+	if s == Metric {
+		return quantities[q].metric
+	}
+	return quantities[q].imperial
+}
+
+// Convert converts an imperial value to the system, rounding metric values
+// to a sensible precision
+func (q Quantity) Convert(v float64, s System) float64 {
+	if s != Metric {
+		return v
+	}
+	p := math.Pow(10, float64(quantities[q].decimals))
+	return math.Round(v*quantities[q].factor*p) / p
+}
+
+// Factor returns the factor converting an imperial value to the system,
+// without the rounding of Convert
+func (q Quantity) Factor(s System) float64 {
+	if s != Metric {
+		return 1
+	}
+	return quantities[q].factor
+}
+
+// ToImperial converts a value given in the system back to imperial units
+func (q Quantity) ToImperial(v float64, s System) float64 {
+	if s != Metric {
+		return v
+	}
+	return v / quantities[q].factor
+}
+
+// Fields maps the dimensional columns of aircraft_data to their quantity
+var Fields = map[string]Quantity{
+	"approach_speed_knot":                    Speed,
+	"approach_speed_minimum_knot":            Speed,
+	"approach_speed_maximum_knot":            Speed,
+	"wingspan_ft_without_winglets_sharklets": Length,
+	"wingspan_ft_with_winglets_sharklets":    Length,
+	"length_ft":                              Length,
+	"tail_height_at_oew_ft":                  Length,
+	"wheelbase_ft":                           Length,
+	"cockpit_to_main_gear_ft":                Length,
+	"main_gear_width_ft":                     Length,
+	"mtow_lb":                                Mass,
+	"malw_lb":                                Mass,
+	"parking_area_ft2":                       Area,
+	"rotor_diameter_ft":                      Length,
+}
+
+// metricWords maps the unit words in the names of dimensional columns to
+// their metric counterparts
+var metricWords = map[string]string{
+	"ft":   "m",
+	"ft2":  "m2",
+	"lb":   "kg",
+	"knot": "kmh",
+}
+
+// Key returns the JSON name of a column in the system. Dimensional columns
+// are named after their stored unit, so in metric the unit word is
+// swapped, e.g. length_ft becomes length_m; other names are unchanged.
+func Key(column string, s System) string {
+	if _, ok := Fields[column]; !ok {
+		return column
+	}
+	return Name(column, s)
+}
+
+// Name swaps the unit words of any field name for the system, e.g.
+// max_wingspan_ft becomes max_wingspan_m in metric
+func Name(name string, s System) string {
+	if s != Metric {
+		return name
+	}
+
+	words := strings.Split(name, "_")
+	for i, w := range words {
+		if m, ok := metricWords[w]; ok {
+			words[i] = m
+		}
+	}
+	return strings.Join(words, "_")
+}
+
+// Rename rewrites every object key of a JSON document with Name, keeping
+// their order. Responses whose structs are named after imperial units use
+// it once their values are in the system.
+func Rename(data []byte, s System) ([]byte, error) {
+	if s != Metric {
+		return data, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var buf bytes.Buffer
+	if err := rename(dec, &buf, s); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// rename copies the next JSON value from dec to buf, renaming object keys
+func rename(dec *json.Decoder, buf *bytes.Buffer, s System) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		value, err := json.Marshal(tok)
+		buf.Write(value)
+		return err
+	}
+
+	buf.WriteRune(rune(delim))
+	for i := 0; dec.More(); i++ {
+		if i > 0 {
+			buf.WriteByte(',')
+		}
+		if delim == '{' {
+			key, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			name, _ := json.Marshal(Name(key.(string), s))
+			buf.Write(name)
+			buf.WriteByte(':')
+		}
+		if err := rename(dec, buf, s); err != nil {
+			return err
+		}
+	}
+	end, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	buf.WriteRune(rune(end.(json.Delim)))
+	return nil
+}
+
+// Labels returns the unit of every dimensional column in the system, keyed
+// by the name of the column in that system
+func Labels(s System) map[string]string {
+	labels := make(map[string]string, len(Fields))
+	for name, q := range Fields {
+		labels[Key(name, s)] = q.Unit(s)
+	}
+	return labels
+}
+
+// Decimal reads a nullable numeric column. NULL and values that do not
+// fit a float64 are nil. Every package reading the decimal columns of
+// aircraft_data goes through it, so they agree on missing values.
+func Decimal(v pgtype.Numeric) *float64 {
+	f, err := v.Float64Value()
+	if err != nil || !f.Valid {
+		return nil
+	}
+	return &f.Float64
+}
+
+// Convert returns a copy of the aircraft with every dimensional field in
+// the system. The struct keeps its imperial field names; Aircraft renames
+// them when serialized.
+func Convert(a db.AircraftDatum, s System) db.AircraftDatum {
+	if s != Metric {
+		return a
+	}
+
+	for _, f := range []struct {
+		value *pgtype.Int4
+		q     Quantity
+	}{
+		{&a.ApproachSpeedKnot, Speed},
+		{&a.ApproachSpeedMinimumKnot, Speed},
+		{&a.ApproachSpeedMaximumKnot, Speed},
+		{&a.MtowLb, Mass},
+		{&a.MalwLb, Mass},
+	} {
+		if f.value.Valid {
+			f.value.Int32 = int32(f.q.Convert(float64(f.value.Int32), s))
+		}
+	}
+
+	for _, f := range []struct {
+		value *pgtype.Numeric
+		q     Quantity
+	}{
+		{&a.WingspanFtWithoutWingletsSharklets, Length},
+		{&a.WingspanFtWithWingletsSharklets, Length},
+		{&a.LengthFt, Length},
+		{&a.TailHeightAtOewFt, Length},
+		{&a.WheelbaseFt, Length},
+		{&a.CockpitToMainGearFt, Length},
+		{&a.MainGearWidthFt, Length},
+		{&a.ParkingAreaFt2, Area},
+		{&a.RotorDiameterFt, Length},
+	} {
+		v := Decimal(*f.value)
+		if v == nil {
+			continue
+		}
+		var converted pgtype.Numeric
+		if err := converted.Scan(strconv.FormatFloat(f.q.Convert(*v, s), 'f', -1, 64)); err == nil {
+			*f.value = converted
+		}
+	}
+
+	return a
+}
+
+// Aircraft is an aircraft in a unit system, serialized as the aircraft's
+// own fields plus a units object naming the unit of each dimensional field.
+// Dimensional fields are named with Key, so metric values never appear
+// under an imperial name.
+type Aircraft struct {
+	db.AircraftDatum
+	Units map[string]string `json:"units"`
+
+	system System
+}
+
+// NewAircraft converts an aircraft to the system
+func NewAircraft(a db.AircraftDatum, s System) Aircraft {
+	return Aircraft{AircraftDatum: Convert(a, s), Units: Labels(s), system: s}
+}
+
+// datumFields lists the JSON names of the fields of db.AircraftDatum in
+// declaration order
+var datumFields = func() []string {
+	t := reflect.TypeOf(db.AircraftDatum{})
+	names := make([]string, 0, t.NumField())
+	for i := 0; i < t.NumField(); i++ {
+		if name, _, _ := strings.Cut(t.Field(i).Tag.Get("json"), ","); name != "" && name != "-" {
+			names = append(names, name)
+		}
+	}
+	return names
+}()
+
+// MarshalJSON writes the fields of the aircraft in declaration order,
+// naming dimensional fields after the unit of the system
+func (a Aircraft) MarshalJSON() ([]byte, error) {
+	data, err := json.Marshal(a.AircraftDatum)
+	if err != nil {
+		return nil, err
+	}
+	var values map[string]json.RawMessage
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for _, name := range datumFields {
+		key, _ := json.Marshal(Key(name, a.system))
+		buf.Write(key)
+		buf.WriteByte(':')
+		buf.Write(values[name])
+		buf.WriteByte(',')
+	}
+
+	unitLabels, err := json.Marshal(a.Units)
+	if err != nil {
+		return nil, err
+	}
+	buf.WriteString(`"units":`)
+	buf.Write(unitLabels)
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// NewAircraftList converts a list of aircraft to the system
+func NewAircraftList(aircraft []db.AircraftDatum, s System) []Aircraft {
+	out := make([]Aircraft, len(aircraft))
+	for i, a := range aircraft {
+		out[i] = NewAircraft(a, s)
+	}
+	return out
+}
+
+type contextKey struct{}
+
+// WithSystem returns a context carrying the unit system of a request
+func WithSystem(ctx context.Context, s System) context.Context {
+	return context.WithValue(ctx, contextKey{}, s)
+}
+
+// FromContext returns the unit system of a request, imperial by default
+func FromContext(ctx context.Context) System {
+	if s, ok := ctx.Value(contextKey{}).(System); ok {
+		return s
+	}
+	return Imperial
+}
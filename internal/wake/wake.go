@@ -0,0 +1,288 @@
+// Package wake looks up in-trail wake turbulence separation between a
+// leading and a following aircraft. The separation tables of each scheme
+// ship as versioned JSON files embedded from the schemes directory.
+package wake
+
+import (
+	"embed"
+	"encoding/json"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/jackc/pgx/v5/pgtype"
+)
+
+//go:embed schemes/*.json
+var schemeFiles embed.FS
+
+// DefaultScheme is the scheme the separation matrix starts with, being the
+// one currently in use by the FAA
+const DefaultScheme = "recat_cwt"
+
+// MaxMatrixTypes caps the number of types in a separation matrix
+const MaxMatrixTypes = 12
+
+// Scheme is one version of a wake separation scheme. Aircraft are placed in
+// a category from Column, after applying Types overrides by ICAO code and
+// Aliases of stored values. Pairs missing from SeparationNM, keyed leader
+// then follower, only need minimum radar separation.
+type Scheme struct {
+	ID           string                        `json:"id"`
+	Name         string                        `json:"name"`
+	Version      string                        `json:"version"`
+	Revision     int                           `json:"revision"`
+	Source       string                        `json:"source"`
+	Column       string                        `json:"column"`
+	MinimumNM    float64                       `json:"minimum_nm"`
+	Categories   []string                      `json:"categories"`
+	Aliases      map[string]string             `json:"aliases,omitempty"`
+	Types        map[string]string             `json:"types,omitempty"`
+	SeparationNM map[string]map[string]float64 `json:"separation_nm"`
+	Notes        []string                      `json:"notes,omitempty"`
+}
+
+// columns reads the category columns a scheme may be based on
+var columns = map[string]func(db.AircraftDatum) pgtype.Text{
+	"icao_wtc":                 func(a db.AircraftDatum) pgtype.Text { return a.IcaoWtc },
+	"faa_weight":               func(a db.AircraftDatum) pgtype.Text { return a.FaaWeight },
+	"cwt":                      func(a db.AircraftDatum) pgtype.Text { return a.Cwt },
+	"one_half_wake_category":   func(a db.AircraftDatum) pgtype.Text { return a.OneHalfWakeCategory },
+	"two_wake_category_appx_a": func(a db.AircraftDatum) pgtype.Text { return a.TwoWakeCategoryAppxA },
+	"two_wake_category_appx_b": func(a db.AircraftDatum) pgtype.Text { return a.TwoWakeCategoryAppxB },
+}
+
+// registry holds every version of each scheme, oldest revision first
+var registry = mustLoad()
+
+func mustLoad() map[string][]Scheme {
+	schemes, err := load()
+	if err != nil {
+		panic("wake: " + err.Error())
+	}
+	return schemes
+}
+
+// load reads and checks the embedded scheme files
+func load() (map[string][]Scheme, error) {
+	entries, err := schemeFiles.ReadDir("schemes")
+	if err != nil {
+		return nil, err
+	}
+
+	schemes := map[string][]Scheme{}
+	for _, entry := range entries {
+		data, err := schemeFiles.ReadFile(path.Join("schemes", entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+
+		var s Scheme
+		if err := json.Unmarshal(data, &s); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		if err := s.validate(); err != nil {
+			return nil, fmt.Errorf("%s: %w", entry.Name(), err)
+		}
+		schemes[s.ID] = append(schemes[s.ID], s)
+	}
+
+	for id := range schemes {
+		sort.Slice(schemes[id], func(i, j int) bool {
+			return schemes[id][i].Revision < schemes[id][j].Revision
+		})
+	}
+	return schemes, nil
+}
+
+// validate checks that a scheme only refers to its own categories
+func (s Scheme) validate() error {
+	if s.ID == "" || s.Version == "" {
+		return fmt.Errorf("id and version are required")
+	}
+	if _, ok := columns[s.Column]; !ok {
+		return fmt.Errorf("unknown column %q", s.Column)
+	}
+
+	known := map[string]bool{}
+	for _, c := range s.Categories {
+		known[c] = true
+	}
+	for _, targets := range []map[string]string{s.Aliases, s.Types} {
+		for _, c := range targets {
+			if !known[c] {
+				return fmt.Errorf("unknown category %q", c)
+			}
+		}
+	}
+	for leader, row := range s.SeparationNM {
+		if !known[leader] {
+			return fmt.Errorf("unknown leader category %q", leader)
+		}
+		for follower := range row {
+			if !known[follower] {
+				return fmt.Errorf("unknown follower category %q", follower)
+			}
+		}
+	}
+	return nil
+}
+
+// Schemes returns the latest version of every scheme, ordered by id
+func Schemes() []Scheme {
+	var latest []Scheme
+	for _, versions := range registry {
+		latest = append(latest, versions[len(versions)-1])
+	}
+	sort.Slice(latest, func(i, j int) bool { return latest[i].ID < latest[j].ID })
+	return latest
+}
+
+// Versions returns every version of every scheme
+func Versions() []Scheme {
+	var all []Scheme
+	for _, s := range Schemes() {
+		all = append(all, registry[s.ID]...)
+	}
+	return all
+}
+
+// Lookup returns a version of a scheme, or its latest when version is empty
+func Lookup(id, version string) (Scheme, error) {
+	versions, ok := registry[strings.ToLower(id)]
+	if !ok {
+		ids := make([]string, 0, len(registry))
+		for _, s := range Schemes() {
+			ids = append(ids, s.ID)
+		}
+		return Scheme{}, fmt.Errorf("unknown scheme %q: must be one of %s", id, strings.Join(ids, ", "))
+	}
+
+	if version == "" {
+		return versions[len(versions)-1], nil
+	}
+	for _, s := range versions {
+		if strings.EqualFold(s.Version, version) {
+			return s, nil
+		}
+	}
+	return Scheme{}, fmt.Errorf("unknown version %q of scheme %s", version, id)
+}
+
+// Category returns the scheme category of an aircraft
+func (s Scheme) Category(a db.AircraftDatum) (string, bool) {
+	if c, ok := s.Types[strings.ToUpper(a.IcaoCode.String)]; ok {
+		return c, true
+	}
+
+	value := columns[s.Column](a)
+	if !value.Valid {
+		return "", false
+	}
+	raw := strings.TrimSpace(value.String)
+	if alias, ok := s.Aliases[raw]; ok {
+		return alias, true
+	}
+	for _, c := range s.Categories {
+		if strings.EqualFold(c, raw) {
+			return c, true
+		}
+	}
+	return "", false
+}
+
+// Separation returns the in-trail separation in nautical miles between
+// leader and follower categories, and whether it is a wake minimum rather
+// than minimum radar separation
+func (s Scheme) Separation(leader, follower string) (float64, bool) {
+	if nm, ok := s.SeparationNM[leader][follower]; ok {
+		return nm, true
+	}
+	return s.MinimumNM, false
+}
+
+// Result is the separation required by a scheme for a pair of types.
+// SeparationNM is nil, with Message set, when a category is unknown.
+type Result struct {
+	Scheme           string   `json:"scheme"`
+	Name             string   `json:"name"`
+	Version          string   `json:"version"`
+	LeaderCategory   string   `json:"leader_category,omitempty"`
+	FollowerCategory string   `json:"follower_category,omitempty"`
+	SeparationNM     *float64 `json:"separation_nm"`
+	WakeMinimum      bool     `json:"wake_minimum"`
+	Message          string   `json:"message,omitempty"`
+}
+
+// Pair returns the separation for a leading and a following type. A type
+// code can match several variants; when their categories differ, the pair
+// needing the most separation applies.
+func (s Scheme) Pair(leaders, followers []db.AircraftDatum) Result {
+	result := Result{Scheme: s.ID, Name: s.Name, Version: s.Version}
+
+	leaderCategories := s.categories(leaders)
+	followerCategories := s.categories(followers)
+	switch {
+	case len(leaderCategories) == 0:
+		result.Message = fmt.Sprintf("The leader has no %s category", s.Name)
+		return result
+	case len(followerCategories) == 0:
+		result.Message = fmt.Sprintf("The follower has no %s category", s.Name)
+		return result
+	}
+
+	for _, leader := range leaderCategories {
+		for _, follower := range followerCategories {
+			nm, wake := s.Separation(leader, follower)
+			if result.SeparationNM == nil || nm > *result.SeparationNM {
+				result.LeaderCategory, result.FollowerCategory = leader, follower
+				result.SeparationNM, result.WakeMinimum = &nm, wake
+			}
+		}
+	}
+	return result
+}
+
+// categories returns the distinct categories of a set of aircraft
+func (s Scheme) categories(aircraft []db.AircraftDatum) []string {
+	var out []string
+	seen := map[string]bool{}
+	for _, a := range aircraft {
+		if c, ok := s.Category(a); ok && !seen[c] {
+			seen[c] = true
+			out = append(out, c)
+		}
+	}
+	return out
+}
+
+// Matrix is the separation of every ordered pair of a set of types, with
+// leaders as rows and followers as columns. Categories holds the category
+// of each type, joining those of its variants when they differ.
+type Matrix struct {
+	Scheme     Scheme     `json:"scheme"`
+	Types      []string   `json:"types"`
+	Categories []string   `json:"categories"`
+	Cells      [][]Result `json:"cells"`
+}
+
+// NewMatrix builds the separation matrix of the types, whose variants are
+// given by type code
+func NewMatrix(s Scheme, types []string, aircraft map[string][]db.AircraftDatum) Matrix {
+	m := Matrix{
+		Scheme:     s,
+		Types:      types,
+		Categories: make([]string, len(types)),
+		Cells:      make([][]Result, len(types)),
+	}
+	for i, leader := range types {
+		m.Categories[i] = strings.Join(s.categories(aircraft[leader]), "/")
+		m.Cells[i] = make([]Result, len(types))
+		for j, follower := range types {
+			m.Cells[i][j] = s.Pair(aircraft[leader], aircraft[follower])
+		}
+	}
+	return m
+}
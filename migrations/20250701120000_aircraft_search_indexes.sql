@@ -0,0 +1,27 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- Full-text index over the searchable document. The expression must match
+-- the document built by internal/search exactly for the planner to use it.
+CREATE INDEX idx_aircraft_search_fts ON aircraft_data USING GIN (
+    to_tsvector('simple', (COALESCE(manufacturer, '') || ' ' || COALESCE(model_faa, '') || ' ' || COALESCE(model_bada, '') || ' ' || COALESCE(icao_code, '') || ' ' || COALESCE(faa_designator, '') || ' ' || COALESCE(remarks, '')))
+);
+
+-- Trigram index over the same document for typo tolerant matching
+CREATE INDEX idx_aircraft_search_trgm ON aircraft_data USING GIN (
+    (COALESCE(manufacturer, '') || ' ' || COALESCE(model_faa, '') || ' ' || COALESCE(model_bada, '') || ' ' || COALESCE(icao_code, '') || ' ' || COALESCE(faa_designator, '') || ' ' || COALESCE(remarks, '')) gin_trgm_ops
+);
+
+-- Case-insensitive indexes for exact code matches
+CREATE INDEX idx_aircraft_icao_code_upper ON aircraft_data (UPPER(icao_code));
+CREATE INDEX idx_aircraft_faa_designator_upper ON aircraft_data (UPPER(faa_designator));
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP INDEX IF EXISTS idx_aircraft_faa_designator_upper;
+DROP INDEX IF EXISTS idx_aircraft_icao_code_upper;
+DROP INDEX IF EXISTS idx_aircraft_search_trgm;
+DROP INDEX IF EXISTS idx_aircraft_search_fts;
+-- +goose StatementEnd
@@ -0,0 +1,80 @@
+-- +goose Up
+-- +goose StatementBegin
+-- Every import of the FAA workbook is recorded as a dataset version
+CREATE TABLE dataset_versions (
+    id SERIAL PRIMARY KEY,
+    source_file TEXT NOT NULL,
+    source_sha256 VARCHAR(64),
+    sheet_name VARCHAR(100) NOT NULL,
+    row_count INTEGER NOT NULL,
+    imported_at TIMESTAMPTZ NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Snapshot of aircraft_data as it stood after each import. Rows keep their
+-- aircraft_data id, so ids are stable within a version.
+CREATE TABLE aircraft_data_history (
+    version_id INTEGER NOT NULL REFERENCES dataset_versions(id) ON DELETE CASCADE,
+    id INTEGER NOT NULL,
+    icao_code VARCHAR(20),
+    faa_designator VARCHAR(30),
+    manufacturer VARCHAR(100),
+    model_faa VARCHAR(100),
+    model_bada VARCHAR(100),
+    physical_class_engine VARCHAR(30),
+    num_engines INTEGER,
+    aac VARCHAR(20),
+    aac_minimum VARCHAR(20),
+    aac_maximum VARCHAR(20),
+    adg VARCHAR(20),
+    tdg VARCHAR(20),
+    approach_speed_knot INTEGER,
+    approach_speed_minimum_knot INTEGER,
+    approach_speed_maximum_knot INTEGER,
+    wingspan_ft_without_winglets_sharklets DECIMAL(8,2),
+    wingspan_ft_with_winglets_sharklets DECIMAL(8,2),
+    length_ft DECIMAL(8,2),
+    tail_height_at_oew_ft DECIMAL(8,2),
+    wheelbase_ft DECIMAL(8,2),
+    cockpit_to_main_gear_ft DECIMAL(8,2),
+    main_gear_width_ft DECIMAL(8,2),
+    mtow_lb INTEGER,
+    malw_lb INTEGER,
+    main_gear_config VARCHAR(20),
+    icao_wtc VARCHAR(30),
+    parking_area_ft2 DECIMAL(10,2),
+    class VARCHAR(30),
+    faa_weight VARCHAR(20),
+    cwt VARCHAR(20),
+    one_half_wake_category VARCHAR(20),
+    two_wake_category_appx_a VARCHAR(20),
+    two_wake_category_appx_b VARCHAR(20),
+    rotor_diameter_ft DECIMAL(8,2),
+    srs VARCHAR(20),
+    lahso VARCHAR(20),
+    faa_registry VARCHAR(20),
+    registration_count INTEGER,
+    tmfs_operations_fy24 INTEGER,
+    remarks TEXT,
+    last_update VARCHAR(50),
+    created_at TIMESTAMP,
+    updated_at TIMESTAMP,
+    PRIMARY KEY (version_id, id)
+);
+
+CREATE INDEX idx_aircraft_history_icao_code_upper ON aircraft_data_history (version_id, UPPER(icao_code));
+CREATE INDEX idx_aircraft_history_faa_designator_upper ON aircraft_data_history (version_id, UPPER(faa_designator));
+
+-- Keep data imported before versioning as the first version
+INSERT INTO dataset_versions (source_file, sheet_name, row_count)
+SELECT 'existing data', 'ACD_Data', COUNT(*) FROM aircraft_data
+HAVING COUNT(*) > 0;
+
+INSERT INTO aircraft_data_history
+SELECT v.id, a.* FROM aircraft_data a CROSS JOIN dataset_versions v;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS aircraft_data_history;
+DROP TABLE IF EXISTS dataset_versions;
+-- +goose StatementEnd
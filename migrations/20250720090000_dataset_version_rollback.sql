@@ -0,0 +1,11 @@
+-- +goose Up
+-- +goose StatementBegin
+-- A rollback marks the version it undoes instead of deleting it, so the
+-- record and snapshot of every import are kept
+ALTER TABLE dataset_versions ADD COLUMN rolled_back_at TIMESTAMPTZ;
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+ALTER TABLE dataset_versions DROP COLUMN IF EXISTS rolled_back_at;
+-- +goose StatementEnd
@@ -0,0 +1,139 @@
+package client
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+	"time"
+)
+
+// Aircraft is an aircraft type in the typed v2 representation. Missing
+// values are nil and each group of measurements names its unit.
+type Aircraft struct {
+	ID             int32          `json:"id"`
+	IcaoCode       *string        `json:"icao_code"`
+	FaaDesignator  *string        `json:"faa_designator"`
+	Manufacturer   *string        `json:"manufacturer"`
+	Model          *string        `json:"model"`
+	ModelBada      *string        `json:"model_bada"`
+	Classification Classification `json:"classification"`
+	ApproachSpeed  ApproachSpeed  `json:"approach_speed"`
+	Dimensions     Dimensions     `json:"dimensions"`
+	Weights        Weights        `json:"weights"`
+	Wake           Wake           `json:"wake"`
+	Operations     Operations     `json:"operations"`
+	Remarks        *string        `json:"remarks"`
+	LastUpdate     *string        `json:"last_update"`
+}
+
+// Classification holds the design groups and physical class
+type Classification struct {
+	AircraftClass *string `json:"aircraft_class"`
+	PhysicalClass *string `json:"physical_class"`
+	NumEngines    *int    `json:"num_engines"`
+	AAC           *string `json:"aac"`
+	AACMinimum    *string `json:"aac_minimum"`
+	AACMaximum    *string `json:"aac_maximum"`
+	ADG           *string `json:"adg"`
+	TDG           *string `json:"tdg"`
+}
+
+// ApproachSpeed holds the typical approach speed and its range, in knots
+// or km/h
+type ApproachSpeed struct {
+	Unit    string `json:"unit"`
+	Typical *int   `json:"typical"`
+	Minimum *int   `json:"minimum"`
+	Maximum *int   `json:"maximum"`
+}
+
+// Dimensions holds the physical dimensions. Lengths are in Unit and the
+// parking area in AreaUnit.
+type Dimensions struct {
+	Unit                    string   `json:"unit"`
+	AreaUnit                string   `json:"area_unit"`
+	WingspanWithoutWinglets *float64 `json:"wingspan_without_winglets"`
+	WingspanWithWinglets    *float64 `json:"wingspan_with_winglets"`
+	Length                  *float64 `json:"length"`
+	TailHeightAtOew         *float64 `json:"tail_height_at_oew"`
+	Wheelbase               *float64 `json:"wheelbase"`
+	CockpitToMainGear       *float64 `json:"cockpit_to_main_gear"`
+	MainGearWidth           *float64 `json:"main_gear_width"`
+	MainGearConfig          *string  `json:"main_gear_config"`
+	RotorDiameter           *float64 `json:"rotor_diameter"`
+	ParkingArea             *float64 `json:"parking_area"`
+}
+
+// Weights holds the certificated weights
+type Weights struct {
+	Unit string `json:"unit"`
+	MTOW *int   `json:"mtow"`
+	MALW *int   `json:"malw"`
+}
+
+// Wake holds the wake turbulence categories of each scheme
+type Wake struct {
+	IcaoWtc         *string `json:"icao_wtc"`
+	FaaWeightClass  *string `json:"faa_weight_class"`
+	CWT             *string `json:"cwt"`
+	Recat15         *string `json:"recat_1_5"`
+	Recat2AppendixA *string `json:"recat_2_appendix_a"`
+	Recat2AppendixB *string `json:"recat_2_appendix_b"`
+}
+
+// Operations holds operational and registry data
+type Operations struct {
+	SRS                *string `json:"srs"`
+	LahsoGroup         *int    `json:"lahso_group"`
+	FaaRegistered      *bool   `json:"faa_registered"`
+	RegistrationCount  *int    `json:"registration_count"`
+	TmfsOperationsFY24 *int    `json:"tmfs_operations_fy24"`
+}
+
+// Health is the status of the service and its database
+type Health struct {
+	Status        string    `json:"status"`
+	Database      string    `json:"database"`
+	AircraftCount int64     `json:"aircraft_count"`
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Get returns the aircraft with a database id. Ids change across
+// re-imports, so prefer ByICAO or ByFAA for stored references.
+func (c *Client) Get(ctx context.Context, id int32) (*Aircraft, error) {
+	var aircraft Aircraft
+	if err := c.get(ctx, "/api/v2/aircraft/"+strconv.Itoa(int(id)), nil, &aircraft); err != nil {
+		return nil, err
+	}
+	return &aircraft, nil
+}
+
+// ByICAO returns every aircraft variant with an ICAO type designator,
+// matched case-insensitively
+func (c *Client) ByICAO(ctx context.Context, code string) ([]Aircraft, error) {
+	var aircraft []Aircraft
+	if err := c.get(ctx, "/api/v2/aircraft/icao/"+url.PathEscape(code), nil, &aircraft); err != nil {
+		return nil, err
+	}
+	return aircraft, nil
+}
+
+// ByFAA returns every aircraft variant with an FAA designator, matched
+// case-insensitively
+func (c *Client) ByFAA(ctx context.Context, designator string) ([]Aircraft, error) {
+	var aircraft []Aircraft
+	if err := c.get(ctx, "/api/v2/aircraft/faa/"+url.PathEscape(designator), nil, &aircraft); err != nil {
+		return nil, err
+	}
+	return aircraft, nil
+}
+
+// Health checks the service. An unreachable database is reported as an
+// *Error with status 503 once retries are exhausted.
+func (c *Client) Health(ctx context.Context) (*Health, error) {
+	var health Health
+	if err := c.get(ctx, "/health", nil, &health); err != nil {
+		return nil, err
+	}
+	return &health, nil
+}
@@ -0,0 +1,183 @@
+// Package client is a Go client for the FAA Aircraft Search API. It calls
+// the typed v2 aircraft endpoints, retries server errors with backoff and
+// pages through search results with iterators.
+//
+//	c, err := client.New("http://localhost:8080")
+//	if err != nil {
+//		return err
+//	}
+//	for aircraft, err := range c.SearchAll(ctx, client.SearchParams{Query: "boeing"}) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(*aircraft.IcaoCode)
+//	}
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// DefaultRetries is how often a request failing with a 5xx status or a
+	// network error is retried
+	DefaultRetries = 3
+	// DefaultBackoff is the wait before the first retry; it doubles on
+	// every further retry
+	DefaultBackoff = 200 * time.Millisecond
+)
+
+// Client calls the API. It is safe for concurrent use.
+type Client struct {
+	baseURL    *url.URL
+	httpClient *http.Client
+	retries    int
+	backoff    time.Duration
+	units      string
+}
+
+// Option configures a Client
+type Option func(*Client)
+
+// WithHTTPClient sets the HTTP client used for requests, e.g. to set a
+// timeout or transport. The default is http.DefaultClient.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) {
+		c.httpClient = hc
+	}
+}
+
+// WithRetries sets how often failed requests are retried and the initial
+// backoff. Zero retries disables retrying.
+func WithRetries(retries int, backoff time.Duration) Option {
+	return func(c *Client) {
+		c.retries = retries
+		c.backoff = backoff
+	}
+}
+
+// WithUnits requests dimensional fields in a unit system, "imperial" or
+// "metric". The server default is imperial.
+func WithUnits(system string) Option {
+	return func(c *Client) {
+		c.units = system
+	}
+}
+
+// New returns a client for the API at baseURL, e.g. http://localhost:8080
+func New(baseURL string, opts ...Option) (*Client, error) {
+	u, err := url.Parse(strings.TrimRight(baseURL, "/"))
+	if err != nil {
+		return nil, fmt.Errorf("client: invalid base URL: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return nil, fmt.Errorf("client: invalid base URL %q: scheme must be http or https", baseURL)
+	}
+
+	c := &Client{
+		baseURL:    u,
+		httpClient: http.DefaultClient,
+		retries:    DefaultRetries,
+		backoff:    DefaultBackoff,
+	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	return c, nil
+}
+
+// Error is an error response of the API. Code is the machine readable
+// error such as "not_found" or "invalid_filter".
+type Error struct {
+	StatusCode int
+	Code       string
+	Message    string
+}
+
+func (e *Error) Error() string {
+	if e.Message == "" {
+		return fmt.Sprintf("client: %d %s", e.StatusCode, e.Code)
+	}
+	return fmt.Sprintf("client: %d %s: %s", e.StatusCode, e.Code, e.Message)
+}
+
+// IsNotFound reports whether err is a 404 response of the API
+func IsNotFound(err error) bool {
+	var apiErr *Error
+	return errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusNotFound
+}
+
+// get sends a GET request and decodes the JSON response into out,
+// retrying server errors and network failures. The path must already be
+// escaped, as url.PathEscape escapes a path segment.
+func (c *Client) get(ctx context.Context, path string, query url.Values, out any) error {
+	if query == nil {
+		query = url.Values{}
+	}
+	if c.units != "" && query.Get("units") == "" {
+		query.Set("units", c.units)
+	}
+
+	u := c.baseURL.JoinPath(path)
+	u.RawQuery = query.Encode()
+
+	var err error
+	for attempt := 0; ; attempt++ {
+		var retry bool
+		retry, err = c.try(ctx, u.String(), out)
+		if err == nil || !retry || attempt >= c.retries {
+			return err
+		}
+
+		wait := c.backoff << attempt
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// try sends a single request and reports whether a failure may be retried
+func (c *Client) try(ctx context.Context, target string, out any) (bool, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, target, nil)
+	if err != nil {
+		return false, fmt.Errorf("client: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		// Network errors are retried unless the caller gave up
+		return ctx.Err() == nil, fmt.Errorf("client: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 400 {
+		apiErr := &Error{StatusCode: resp.StatusCode}
+		var body struct {
+			Error   string `json:"error"`
+			Message string `json:"message"`
+		}
+		if data, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20)); err == nil && json.Unmarshal(data, &body) == nil {
+			apiErr.Code, apiErr.Message = body.Error, body.Message
+		}
+		if apiErr.Code == "" {
+			apiErr.Code = strings.ToLower(strings.ReplaceAll(http.StatusText(resp.StatusCode), " ", "_"))
+		}
+		return resp.StatusCode >= 500, apiErr
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return false, fmt.Errorf("client: decoding response: %w", err)
+	}
+	return false, nil
+}
@@ -0,0 +1,308 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/faa-aircraft-search/internal/database"
+	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/dto"
+	"github.com/dukerupert/faa-aircraft-search/internal/handler"
+	"github.com/dukerupert/faa-aircraft-search/internal/server"
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+// newTestClient starts the handler and returns a client for it that
+// retries without waiting
+func newTestClient(t *testing.T, h http.Handler, opts ...Option) *Client {
+	t.Helper()
+	srv := httptest.NewServer(h)
+	t.Cleanup(srv.Close)
+
+	c, err := New(srv.URL, append([]Option{WithRetries(2, time.Millisecond)}, opts...)...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// newRouterClient returns a client for the real router. Without a
+// database only requests rejected before any query can be served.
+func newRouterClient(t *testing.T, database *database.Database, opts ...Option) *Client {
+	t.Helper()
+	return newTestClient(t, server.New(handler.New(database)), opts...)
+}
+
+// testDatabase connects to the migrated database at TEST_DATABASE_URL,
+// skipping the test when it is not set
+func testDatabase(t *testing.T) *database.Database {
+	t.Helper()
+	url := os.Getenv("TEST_DATABASE_URL")
+	if url == "" {
+		t.Skip("TEST_DATABASE_URL is not set")
+	}
+
+	pool, err := pgxpool.New(context.Background(), url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(pool.Close)
+	return &database.Database{Pool: pool, Queries: db.New(pool)}
+}
+
+// apiError asserts that err is an API error with the status and code
+func apiError(t *testing.T, err error, status int, code string) {
+	t.Helper()
+	var apiErr *Error
+	if !errors.As(err, &apiErr) {
+		t.Fatalf("expected an *Error, got %v", err)
+	}
+	if apiErr.StatusCode != status || apiErr.Code != code {
+		t.Fatalf("expected %d %s, got %d %s", status, code, apiErr.StatusCode, apiErr.Code)
+	}
+}
+
+func TestSearchInvalidSort(t *testing.T) {
+	c := newRouterClient(t, nil)
+
+	_, err := c.Search(context.Background(), SearchParams{Sort: []string{"no_such_column"}})
+	apiError(t, err, http.StatusBadRequest, "invalid_sort")
+}
+
+func TestInvalidUnits(t *testing.T) {
+	c := newRouterClient(t, nil, WithUnits("furlongs"))
+
+	_, err := c.Search(context.Background(), SearchParams{})
+	apiError(t, err, http.StatusBadRequest, "invalid_units")
+}
+
+// TestLookupPathEscaping sends a code that is only blank once unescaped.
+// Escaping it twice would reach the database as a literal "%20".
+func TestLookupPathEscaping(t *testing.T) {
+	c := newRouterClient(t, nil)
+
+	_, err := c.ByICAO(context.Background(), "  ")
+	apiError(t, err, http.StatusBadRequest, "invalid_code")
+}
+
+func TestLookupPath(t *testing.T) {
+	var path string
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.EscapedPath()
+		w.Write([]byte("[]"))
+	}))
+
+	if _, err := c.ByFAA(context.Background(), "A/B C"); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/api/v2/aircraft/faa/A%2FB%20C"; path != want {
+		t.Fatalf("expected path %s, got %s", want, path)
+	}
+}
+
+func TestBaseURLPath(t *testing.T) {
+	var path string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		w.Write([]byte(`{}`))
+	}))
+	defer srv.Close()
+
+	c, err := New(srv.URL + "/faa/")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := c.Get(context.Background(), 7); err != nil {
+		t.Fatal(err)
+	}
+	if want := "/faa/api/v2/aircraft/7"; path != want {
+		t.Fatalf("expected path %s, got %s", want, path)
+	}
+}
+
+// TestRetry fails the first requests with 503 before the real router
+// answers, which must not be retried
+func TestRetry(t *testing.T) {
+	var requests atomic.Int32
+	router := server.New(handler.New(nil))
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) <= 2 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		router.ServeHTTP(w, r)
+	}))
+
+	_, err := c.Search(context.Background(), SearchParams{Sort: []string{"no_such_column"}})
+	apiError(t, err, http.StatusBadRequest, "invalid_sort")
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestRetriesExhausted(t *testing.T) {
+	var requests atomic.Int32
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+
+	_, err := c.Health(context.Background())
+	apiError(t, err, http.StatusServiceUnavailable, "service_unavailable")
+	if n := requests.Load(); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+}
+
+// TestPagesRanked serves a relevance-ranked search the way the API does,
+// without cursors, and expects the pages to be read by number
+func TestPagesRanked(t *testing.T) {
+	const total, limit = 7, 3
+	c := newTestClient(t, http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		page, _ := strconv.Atoi(r.URL.Query().Get("page"))
+		if page == 0 {
+			page = 1
+		}
+		resp := SearchPage{Page: page, Limit: limit}
+		for id := (page-1)*limit + 1; id <= min(page*limit, total); id++ {
+			resp.Aircraft = append(resp.Aircraft, Aircraft{ID: int32(id)})
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+
+	var ids []int32
+	for a, err := range c.SearchAll(context.Background(), SearchParams{Query: "boeing", Limit: limit}) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		ids = append(ids, a.ID)
+	}
+	if len(ids) != total || ids[total-1] != total {
+		t.Fatalf("expected aircraft 1 to %d, got %v", total, ids)
+	}
+}
+
+// TestAircraftMatchesDTO guards the client types against drifting from
+// the v2 representation
+func TestAircraftMatchesDTO(t *testing.T) {
+	s, n := "x", 1
+	f, b := 1.5, true
+	want, err := json.Marshal(dto.Aircraft{
+		ID:             1,
+		IcaoCode:       &s,
+		Classification: dto.Classification{ADG: &s, NumEngines: &n},
+		ApproachSpeed:  dto.ApproachSpeed{Unit: "kt", Typical: &n},
+		Dimensions:     dto.Dimensions{Unit: "ft", Length: &f, ParkingArea: &f},
+		Weights:        dto.Weights{Unit: "lb", MTOW: &n},
+		Wake:           dto.Wake{Recat15: &s},
+		Operations:     dto.Operations{FaaRegistered: &b, TmfsOperationsFY24: &n},
+		LastUpdate:     &s,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var aircraft Aircraft
+	if err := json.Unmarshal(want, &aircraft); err != nil {
+		t.Fatal(err)
+	}
+	got, err := json.Marshal(aircraft)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(got) != string(want) {
+		t.Fatalf("client.Aircraft does not round-trip the v2 JSON:\n got %s\nwant %s", got, want)
+	}
+}
+
+func TestSearchAllRanked(t *testing.T) {
+	c := newRouterClient(t, testDatabase(t))
+	ctx := context.Background()
+
+	p := SearchParams{Query: "a", Limit: 5}
+	first, err := c.Search(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if *first.Total <= int64(p.Limit) {
+		t.Skipf("only %d aircraft match, too few to page", *first.Total)
+	}
+
+	seen := map[int32]bool{}
+	for a, err := range c.SearchAll(ctx, p) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		if seen[a.ID] {
+			t.Fatalf("aircraft %d returned twice", a.ID)
+		}
+		seen[a.ID] = true
+	}
+	if int64(len(seen)) != *first.Total {
+		t.Fatalf("expected %d aircraft, got %d", *first.Total, len(seen))
+	}
+}
+
+func TestSearchAllSorted(t *testing.T) {
+	c := newRouterClient(t, testDatabase(t))
+	ctx := context.Background()
+
+	p := SearchParams{Sort: []string{"icao_code"}, Limit: 100}
+	first, err := c.Search(ctx, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var n int64
+	for _, err := range c.SearchAll(ctx, p) {
+		if err != nil {
+			t.Fatal(err)
+		}
+		n++
+	}
+	if n != *first.Total {
+		t.Fatalf("expected %d aircraft, got %d", *first.Total, n)
+	}
+}
+
+func TestLookups(t *testing.T) {
+	c := newRouterClient(t, testDatabase(t))
+	ctx := context.Background()
+
+	page, err := c.Search(ctx, SearchParams{Sort: []string{"icao_code"}, Limit: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Aircraft) == 0 || page.Aircraft[0].IcaoCode == nil {
+		t.Skip("no aircraft with an ICAO code")
+	}
+	want := page.Aircraft[0]
+
+	got, err := c.Get(ctx, want.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got.ID != want.ID {
+		t.Fatalf("expected aircraft %d, got %d", want.ID, got.ID)
+	}
+
+	variants, err := c.ByICAO(ctx, *want.IcaoCode)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(variants) == 0 {
+		t.Fatalf("no aircraft found for ICAO code %s", *want.IcaoCode)
+	}
+
+	if _, err := c.Get(ctx, -1); !IsNotFound(err) {
+		t.Fatalf("expected not found, got %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package client
+
+import (
+	"context"
+	"iter"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// SearchParams holds the query, filters and paging of a search. Every
+// field is optional; an empty search browses all aircraft.
+type SearchParams struct {
+	// Query is a free text search over codes, manufacturer and model
+	Query string
+	// Filters maps categorical columns such as "adg" to accepted values
+	Filters map[string][]string
+	// Ranges maps numeric columns such as "mtow_lb" to inclusive bounds
+	Ranges map[string]Range
+	// Sort lists sort keys such as "mtow_lb:desc"
+	Sort []string
+	// Facets lists the columns to count per value or bucket
+	Facets []string
+	// Page and Limit select a page by offset; Limit is 1 to 100
+	Page  int
+	Limit int
+	// Cursor continues from the NextCursor or PrevCursor of a page
+	Cursor string
+	// SkipTotal skips counting the matching aircraft
+	SkipTotal bool
+	// Version searches a dataset version instead of the live data
+	Version int
+}
+
+// Range is an inclusive bound on a numeric column. A nil bound is open.
+type Range struct {
+	Min *float64 `json:"min,omitempty"`
+	Max *float64 `json:"max,omitempty"`
+}
+
+// Facet holds the bucket counts of a column. Unit names the unit of the
+// bucket bounds of dimensional histograms.
+type Facet struct {
+	Type    string   `json:"type"`
+	Unit    string   `json:"unit,omitempty"`
+	Buckets []Bucket `json:"buckets"`
+}
+
+// Bucket is a facet bucket. Terms buckets set Value; histogram buckets
+// cover Min inclusive to Max exclusive.
+type Bucket struct {
+	Value string   `json:"value,omitempty"`
+	Min   *float64 `json:"min,omitempty"`
+	Max   *float64 `json:"max,omitempty"`
+	Count int64    `json:"count"`
+}
+
+// SearchPage is a page of search results. Total is nil when SkipTotal was
+// set, and a cursor is empty when there is no page in that direction.
+type SearchPage struct {
+	Aircraft   []Aircraft          `json:"aircraft"`
+	Total      *int64              `json:"total"`
+	Page       int                 `json:"page"`
+	Limit      int                 `json:"limit"`
+	NextCursor string              `json:"next_cursor"`
+	PrevCursor string              `json:"prev_cursor"`
+	Filters    map[string][]string `json:"filters"`
+	Ranges     map[string]Range    `json:"ranges"`
+	Sort       string              `json:"sort"`
+	Facets     map[string]Facet    `json:"facets"`
+}
+
+// values encodes the parameters as the query string of the search API
+func (p SearchParams) values() url.Values {
+	v := url.Values{}
+	if p.Query != "" {
+		v.Set("q", p.Query)
+	}
+	for column, values := range p.Filters {
+		v.Set(column, strings.Join(values, ","))
+	}
+	for column, r := range p.Ranges {
+		if r.Min != nil {
+			v.Set("min_"+column, strconv.FormatFloat(*r.Min, 'f', -1, 64))
+		}
+		if r.Max != nil {
+			v.Set("max_"+column, strconv.FormatFloat(*r.Max, 'f', -1, 64))
+		}
+	}
+	if len(p.Sort) > 0 {
+		v.Set("sort", strings.Join(p.Sort, ","))
+	}
+	if len(p.Facets) > 0 {
+		v.Set("facets", strings.Join(p.Facets, ","))
+	}
+	if p.Page > 0 {
+		v.Set("page", strconv.Itoa(p.Page))
+	}
+	if p.Limit > 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Cursor != "" {
+		v.Set("cursor", p.Cursor)
+	}
+	if p.SkipTotal {
+		v.Set("include_total", "false")
+	}
+	if p.Version > 0 {
+		v.Set("version", strconv.Itoa(p.Version))
+	}
+	return v
+}
+
+// Search returns one page of aircraft matching the parameters
+func (c *Client) Search(ctx context.Context, p SearchParams) (*SearchPage, error) {
+	var page SearchPage
+	if err := c.get(ctx, "/api/v2/aircraft/search", p.values(), &page); err != nil {
+		return nil, err
+	}
+	return &page, nil
+}
+
+// Pages iterates over the pages of a search, starting from the page the
+// parameters select. Sorted searches follow next cursors. Text searches
+// ranked by relevance get no cursors, since relevance is computed per
+// query, so they advance by page number instead. Only the first
+// page is counted and faceted. Iteration stops after the first error.
+func (c *Client) Pages(ctx context.Context, p SearchParams) iter.Seq2[*SearchPage, error] {
+	return func(yield func(*SearchPage, error) bool) {
+		ranked := p.ranked()
+		for {
+			page, err := c.Search(ctx, p)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(page, nil) {
+				return
+			}
+
+			switch {
+			case page.NextCursor != "":
+				p.Cursor = page.NextCursor
+			case ranked && len(page.Aircraft) > 0 && len(page.Aircraft) == page.Limit:
+				// A full ranked page may be followed by another
+				p.Page = page.Page + 1
+			default:
+				return
+			}
+			p.SkipTotal = true
+			p.Facets = nil
+		}
+	}
+}
+
+// ranked reports whether the API orders the results by relevance, which
+// it does for text searches without a sort or sorted by "relevance" first
+func (p SearchParams) ranked() bool {
+	if strings.TrimSpace(p.Query) == "" {
+		return false
+	}
+	return len(p.Sort) == 0 || strings.HasPrefix(p.Sort[0], "relevance")
+}
+
+// SearchAll iterates over every aircraft matching the parameters, fetching
+// pages as needed. Iteration stops after the first error.
+func (c *Client) SearchAll(ctx context.Context, p SearchParams) iter.Seq2[Aircraft, error] {
+	return func(yield func(Aircraft, error) bool) {
+		for page, err := range c.Pages(ctx, p) {
+			if err != nil {
+				yield(Aircraft{}, err)
+				return
+			}
+			for _, a := range page.Aircraft {
+				if !yield(a, nil) {
+					return
+				}
+			}
+		}
+	}
+}
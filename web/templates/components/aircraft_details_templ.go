@@ -11,6 +11,7 @@ import templruntime "github.com/a-h/templ/runtime"
 import (
 	"fmt"
 	"github.com/dukerupert/faa-aircraft-search/internal/db"
+	"github.com/dukerupert/faa-aircraft-search/internal/units"
 	"github.com/jackc/pgx/v5/pgtype"
 )
 
@@ -56,7 +57,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		var templ_7745c5c3_Var2 string
 		templ_7745c5c3_Var2, templ_7745c5c3_Err = templ.JoinStringErrs(getStringValue(aircraft.FaaDesignator))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 45, Col: 45}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 46, Col: 45}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var2))
 		if templ_7745c5c3_Err != nil {
@@ -69,7 +70,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		var templ_7745c5c3_Var3 string
 		templ_7745c5c3_Var3, templ_7745c5c3_Err = templ.JoinStringErrs(getStringValue(aircraft.ModelFaa))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 45, Col: 83}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 46, Col: 83}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var3))
 		if templ_7745c5c3_Err != nil {
@@ -82,7 +83,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		var templ_7745c5c3_Var4 string
 		templ_7745c5c3_Var4, templ_7745c5c3_Err = templ.JoinStringErrs(getStringValue(aircraft.Manufacturer))
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 47, Col: 81}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 48, Col: 81}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var4))
 		if templ_7745c5c3_Err != nil {
@@ -100,7 +101,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 			var templ_7745c5c3_Var5 string
 			templ_7745c5c3_Var5, templ_7745c5c3_Err = templ.JoinStringErrs(getStringValue(aircraft.IcaoCode))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 49, Col: 113}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 50, Col: 113}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var5))
 			if templ_7745c5c3_Err != nil {
@@ -141,7 +142,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Approach Speed", func() string {
 			if aircraft.ApproachSpeedKnot.Valid {
-				return fmt.Sprintf("%d knots", aircraft.ApproachSpeedKnot.Int32)
+				return fmt.Sprintf("%d %s", aircraft.ApproachSpeedKnot.Int32, unitOf(ctx, units.Speed))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -150,7 +151,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("MTOW", func() string {
 			if aircraft.MtowLb.Valid {
-				return fmt.Sprintf("%d lbs", aircraft.MtowLb.Int32)
+				return fmt.Sprintf("%d %s", aircraft.MtowLb.Int32, unitOf(ctx, units.Mass))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -159,7 +160,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("MALW", func() string {
 			if aircraft.MalwLb.Valid {
-				return fmt.Sprintf("%d lbs", aircraft.MalwLb.Int32)
+				return fmt.Sprintf("%d %s", aircraft.MalwLb.Int32, unitOf(ctx, units.Mass))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -180,9 +181,9 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Wingspan", func() string {
 			if aircraft.WingspanFtWithWingletsSharklets.Valid {
-				return fmt.Sprintf("%s ft (with winglets)", getNumericValue(aircraft.WingspanFtWithWingletsSharklets))
+				return fmt.Sprintf("%s %s (with winglets)", getNumericValue(aircraft.WingspanFtWithWingletsSharklets), unitOf(ctx, units.Length))
 			} else if aircraft.WingspanFtWithoutWingletsSharklets.Valid {
-				return fmt.Sprintf("%s ft", getNumericValue(aircraft.WingspanFtWithoutWingletsSharklets))
+				return fmt.Sprintf("%s %s", getNumericValue(aircraft.WingspanFtWithoutWingletsSharklets), unitOf(ctx, units.Length))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -191,7 +192,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Length", func() string {
 			if aircraft.LengthFt.Valid {
-				return fmt.Sprintf("%s ft", getNumericValue(aircraft.LengthFt))
+				return fmt.Sprintf("%s %s", getNumericValue(aircraft.LengthFt), unitOf(ctx, units.Length))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -200,7 +201,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Tail Height", func() string {
 			if aircraft.TailHeightAtOewFt.Valid {
-				return fmt.Sprintf("%s ft", getNumericValue(aircraft.TailHeightAtOewFt))
+				return fmt.Sprintf("%s %s", getNumericValue(aircraft.TailHeightAtOewFt), unitOf(ctx, units.Length))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -209,7 +210,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Main Gear Width", func() string {
 			if aircraft.MainGearWidthFt.Valid {
-				return fmt.Sprintf("%s ft", getNumericValue(aircraft.MainGearWidthFt))
+				return fmt.Sprintf("%s %s", getNumericValue(aircraft.MainGearWidthFt), unitOf(ctx, units.Length))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -218,7 +219,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 		}
 		templ_7745c5c3_Err = DetailField("Parking Area", func() string {
 			if aircraft.ParkingAreaFt2.Valid {
-				return fmt.Sprintf("%s ft²", getNumericValue(aircraft.ParkingAreaFt2))
+				return fmt.Sprintf("%s %s", getNumericValue(aircraft.ParkingAreaFt2), unitOf(ctx, units.Area))
 			}
 			return "N/A"
 		}()).Render(ctx, templ_7745c5c3_Buffer)
@@ -286,7 +287,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 			return templ_7745c5c3_Err
 		}
 		if aircraft.RotorDiameterFt.Valid {
-			templ_7745c5c3_Err = DetailField("Rotor Diameter", fmt.Sprintf("%s ft", getNumericValue(aircraft.RotorDiameterFt))).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = DetailField("Rotor Diameter", fmt.Sprintf("%s %s", getNumericValue(aircraft.RotorDiameterFt), unitOf(ctx, units.Length))).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -307,7 +308,7 @@ func AircraftDetails(aircraft db.AircraftDatum) templ.Component {
 			var templ_7745c5c3_Var6 string
 			templ_7745c5c3_Var6, templ_7745c5c3_Err = templ.JoinStringErrs(getStringValue(aircraft.Remarks))
 			if templ_7745c5c3_Err != nil {
-				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 163, Col: 90}
+				return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 164, Col: 90}
 			}
 			_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var6))
 			if templ_7745c5c3_Err != nil {
@@ -355,7 +356,7 @@ func DetailField(label, value string) templ.Component {
 		var templ_7745c5c3_Var8 string
 		templ_7745c5c3_Var8, templ_7745c5c3_Err = templ.JoinStringErrs(label)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 173, Col: 55}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 174, Col: 55}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var8))
 		if templ_7745c5c3_Err != nil {
@@ -368,7 +369,7 @@ func DetailField(label, value string) templ.Component {
 		var templ_7745c5c3_Var9 string
 		templ_7745c5c3_Var9, templ_7745c5c3_Err = templ.JoinStringErrs(value)
 		if templ_7745c5c3_Err != nil {
-			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 174, Col: 60}
+			return templ.Error{Err: templ_7745c5c3_Err, FileName: `web/templates/components/aircraft_details.templ`, Line: 175, Col: 60}
 		}
 		_, templ_7745c5c3_Err = templ_7745c5c3_Buffer.WriteString(templ.EscapeString(templ_7745c5c3_Var9))
 		if templ_7745c5c3_Err != nil {
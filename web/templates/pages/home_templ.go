@@ -12,7 +12,7 @@ import "github.com/dukerupert/faa-aircraft-search/web/templates/layout"
 import "github.com/dukerupert/faa-aircraft-search/web/templates/components"
 import "github.com/dukerupert/faa-aircraft-search/internal/db"
 
-func Home(aircraft []db.AircraftDatum, total int64, page int, limit int) templ.Component {
+func Home(aircraft []db.AircraftDatum, total int64, page int, limit int, sort string) templ.Component {
 	return templruntime.GeneratedTemplate(func(templ_7745c5c3_Input templruntime.GeneratedComponentInput) (templ_7745c5c3_Err error) {
 		templ_7745c5c3_W, ctx := templ_7745c5c3_Input.Writer, templ_7745c5c3_Input.Context
 		if templ_7745c5c3_CtxErr := ctx.Err(); templ_7745c5c3_CtxErr != nil {
@@ -61,7 +61,7 @@ func Home(aircraft []db.AircraftDatum, total int64, page int, limit int) templ.C
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
-			templ_7745c5c3_Err = components.AircraftContainer(aircraft, total, page, limit).Render(ctx, templ_7745c5c3_Buffer)
+			templ_7745c5c3_Err = components.AircraftContainer(aircraft, total, page, limit, sort).Render(ctx, templ_7745c5c3_Buffer)
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
@@ -69,6 +69,10 @@ func Home(aircraft []db.AircraftDatum, total int64, page int, limit int) templ.C
 			if templ_7745c5c3_Err != nil {
 				return templ_7745c5c3_Err
 			}
+			templ_7745c5c3_Err = components.CompareBar().Render(ctx, templ_7745c5c3_Buffer)
+			if templ_7745c5c3_Err != nil {
+				return templ_7745c5c3_Err
+			}
 			return nil
 		})
 		templ_7745c5c3_Err = layout.Base("Home").Render(templ.WithChildren(ctx, templ_7745c5c3_Var2), templ_7745c5c3_Buffer)